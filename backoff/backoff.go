@@ -0,0 +1,15 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+func Jittered(attempt int, base, limit time.Duration) time.Duration {
+	delay := limit
+	if attempt < 32 {
+		delay = min(base<<attempt, limit)
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
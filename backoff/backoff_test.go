@@ -0,0 +1,28 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitteredStaysWithinBounds(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{5, 32 * time.Second},
+		{6, time.Minute},
+		{30, time.Minute},
+		{64, time.Minute},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := Jittered(tt.attempt, time.Second, time.Minute)
+			if got < tt.want/2 || got > tt.want {
+				t.Fatalf("Jittered(%d) = %s, want within [%s, %s]", tt.attempt, got, tt.want/2, tt.want)
+			}
+		}
+	}
+}
@@ -2,18 +2,51 @@ package cerrors
 
 import (
 	"fmt"
+	"runtime"
+	"strings"
 )
 
 type (
 	Code int64
 
+	Kind int
+
 	Error struct {
 		Code        Code
 		UserMessage string
 		Origin      error
+
+		Kind      Kind
+		Retryable bool
+		stack     []uintptr
 	}
 )
 
+const (
+	KindUnspecified Kind = iota
+	KindNotFound
+	KindConflict
+	KindUnauthenticated
+	KindUnavailable
+	KindInternal
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindUnauthenticated:
+		return "unauthenticated"
+	case KindUnavailable:
+		return "unavailable"
+	case KindInternal:
+		return "internal"
+	}
+	return "unspecified"
+}
+
 func (e *Error) Error() string {
 	var originErrMessage string
 	if e.Origin != nil {
@@ -21,3 +54,29 @@ func (e *Error) Error() string {
 	}
 	return fmt.Sprintf("internal code: %d; origin message: %s; user message: %s", e.Code, originErrMessage, e.UserMessage)
 }
+
+func (e *Error) Unwrap() error {
+	return e.Origin
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func (e *Error) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		_, _ = fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
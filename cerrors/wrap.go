@@ -1,9 +1,74 @@
 package cerrors
 
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+)
+
+const (
+	maxStackDepth = 32
+)
+
+var (
+	captureStack atomic.Bool
+)
+
+func SetCaptureStack(enabled bool) {
+	captureStack.Store(enabled)
+}
+
 func NewErrorWithUserMessage(code Code, err error, userMessage string) *Error {
-	return &Error{
+	e := &Error{
 		Code:        code,
 		UserMessage: userMessage,
 		Origin:      err,
 	}
+
+	if captureStack.Load() {
+		pcs := make([]uintptr, maxStackDepth)
+		e.stack = pcs[:runtime.Callers(2, pcs)]
+	}
+
+	return e
+}
+
+func (e *Error) WithKind(kind Kind) *Error {
+	e.Kind = kind
+	return e
+}
+
+func (e *Error) WithRetryable(retryable bool) *Error {
+	e.Retryable = retryable
+	return e
+}
+
+func KindOf(err error) Kind {
+	for err != nil {
+		var cErr *Error
+		if !errors.As(err, &cErr) {
+			return KindUnspecified
+		}
+		if cErr.Kind != KindUnspecified {
+			return cErr.Kind
+		}
+		err = cErr.Origin
+	}
+
+	return KindUnspecified
+}
+
+func IsRetryable(err error) bool {
+	for err != nil {
+		var cErr *Error
+		if !errors.As(err, &cErr) {
+			return false
+		}
+		if cErr.Retryable {
+			return true
+		}
+		err = cErr.Origin
+	}
+
+	return false
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"x-bank-users/config"
+	"x-bank-users/core/cleaner"
+	"x-bank-users/core/scheduler"
+	"x-bank-users/infra/archive"
+	"x-bank-users/infra/metrics"
+	"x-bank-users/infra/postgres"
+	"x-bank-users/logging"
+)
+
+const (
+	pushTimeout = 10 * time.Second
+)
+
+var (
+	configFile = flag.String("config", "config.json", "")
+	jobName    = flag.String("job", "", "")
+	dryRun     = flag.Bool("dry-run", false, "")
+)
+
+func main() {
+	flag.Parse()
+
+	conf, err := config.ReadCleaner(*configFile, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logLevel, err := logging.ParseLevel(conf.Log.Level)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var logLevelVar slog.LevelVar
+	logLevelVar.Set(logLevel)
+	logger := logging.New(os.Stdout, &logLevelVar).With(slog.String("component", "cleaner"))
+	slog.SetDefault(logger)
+
+	postgresService, err := postgres.NewService(conf.Postgres.Login, conf.Postgres.Password, conf.Postgres.Host, conf.Postgres.Port, conf.Postgres.DataBase, 2, conf.Postgres.IsolationLevel, conf.Postgres.TxMaxRetries, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer postgresService.Close()
+
+	metricsService := metrics.NewService()
+	archiveService := archive.NewService(conf.Cleaner.AuthHistoryArchiveDir)
+	cleanerService := cleaner.NewService(&postgresService, &postgresService, &archiveService, &metricsService, conf.Cleaner.BatchSize, conf.Cleaner.DryRun || *dryRun, time.Duration(conf.Cleaner.AuthHistoryRetention))
+	jobs := cleanerService.Jobs("", "", "")
+	schedulerService := scheduler.NewService(&postgresService, &postgresService, &metricsService, jobs)
+
+	ctx, cancel := signal.NotifyContext(logging.WithLogger(context.Background(), logger), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	names := []string{*jobName}
+	if *jobName == "" {
+		names = names[:0]
+		for _, job := range jobs {
+			names = append(names, job.Name)
+		}
+	}
+
+	failed := false
+	for _, name := range names {
+		run, err := schedulerService.RunOnce(ctx, name)
+		if err != nil {
+			logger.Error("job failed", slog.String("job", name), slog.String("error", err.Error()))
+			failed = true
+			continue
+		}
+		logger.Info("job completed", slog.String("job", name), slog.String("status", run.Status))
+	}
+
+	if conf.Cleaner.PushgatewayURL != "" {
+		pushCtx, pushCancel := context.WithTimeout(context.WithoutCancel(ctx), pushTimeout)
+		if err = metricsService.Push(pushCtx, conf.Cleaner.PushgatewayURL, "x-bank-users-cleaner"); err != nil {
+			logger.Error("metrics push failed", slog.String("error", err.Error()))
+		}
+		pushCancel()
+	}
+
+	if failed {
+		cancel()
+		postgresService.Close()
+		os.Exit(1)
+	}
+}
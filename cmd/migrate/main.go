@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"x-bank-users/config"
+	"x-bank-users/infra/postgres"
+)
+
+var (
+	configFile = flag.String("config", "config.json", "")
+)
+
+func usage() {
+	_, _ = fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-config file] up [N] | down [N] | status | baseline | force VERSION\n\n"+
+		"baseline marks the initial migration as applied on a database whose schema was created by hand;\n"+
+		"run it once, then up applies the remaining migrations\n\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	conf, err := config.ReadPostgres(*configFile, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	postgresService, err := postgres.NewService(conf.Login, conf.Password, conf.Host, conf.Port, conf.DataBase, 1, conf.IsolationLevel, conf.TxMaxRetries, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer postgresService.Close()
+
+	ctx := context.Background()
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := postgresService.MigrateUp(ctx, stepsArg(0))
+		for _, migration := range applied {
+			log.Printf("applied %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			log.Print("no change")
+		}
+	case "down":
+		reverted, err := postgresService.MigrateDown(ctx, stepsArg(1))
+		for _, migration := range reverted {
+			log.Printf("reverted %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(reverted) == 0 {
+			log.Print("no change")
+		}
+	case "status":
+		status, err := postgresService.MigrationStatus(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("version: %d, dirty: %t\n", status.Version, status.Dirty)
+		for _, migration := range status.Migrations {
+			state := "pending"
+			if migration.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %d_%s\n", state, migration.Version, migration.Name)
+		}
+	case "baseline":
+		migration, err := postgresService.BaselineMigrations(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("baseline %d_%s", migration.Version, migration.Name)
+	case "force":
+		if flag.NArg() != 2 {
+			usage()
+			os.Exit(2)
+		}
+		version, err := strconv.ParseInt(flag.Arg(1), 10, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = postgresService.ForceMigrationVersion(ctx, version); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("forced version %d", version)
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func stepsArg(defaultSteps int) int {
+	if flag.NArg() < 2 {
+		return defaultSteps
+	}
+
+	steps, err := strconv.Atoi(flag.Arg(1))
+	if err != nil || steps < 0 {
+		log.Fatalf("некорректное количество шагов %q", flag.Arg(1))
+	}
+	return steps
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"x-bank-users/config"
+	"x-bank-users/infra/envelope"
+	"x-bank-users/infra/postgres"
+)
+
+var (
+	configFile = flag.String("config", "config.json", "")
+	batchSize  = flag.Int("batch-size", 100, "")
+	legacy     = flag.Bool("legacy", false, "")
+)
+
+func main() {
+	flag.Parse()
+	conf, err := config.Read(*configFile, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var kms envelope.LocalKMS
+	if conf.Encryption.KeyFile != "" {
+		kms, err = envelope.NewLocalKMSFromFile(conf.Encryption.KeyFile)
+	} else {
+		kms, err = envelope.NewLocalKMS(conf.Encryption.CurrentKeyId, conf.Encryption.MasterKeys)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	encryptionService, err := envelope.NewService(&kms, conf.Encryption.BlindIndexKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	postgresService, err := postgres.NewService(conf.Postgres.Login, conf.Postgres.Password, conf.Postgres.Host, conf.Postgres.Port, conf.Postgres.DataBase, conf.Postgres.MaxCons, conf.Postgres.IsolationLevel, conf.Postgres.TxMaxRetries, &encryptionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer postgresService.Close()
+
+	if *legacy {
+		count, err := postgresService.EncryptLegacyPersonalData(context.Background(), *batchSize)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("encrypted %d legacy rows with key %q", count, encryptionService.CurrentKeyId())
+		return
+	}
+
+	count, err := postgresService.ReencryptPersonalData(context.Background(), *batchSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("re-encrypted %d rows with key %q", count, encryptionService.CurrentKeyId())
+}
@@ -2,36 +2,88 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	"x-bank-users/cerrors"
 	"x-bank-users/config"
+	"x-bank-users/core/cleaner"
+	"x-bank-users/core/health"
+	"x-bank-users/core/outbox"
+	"x-bank-users/core/scheduler"
 	"x-bank-users/core/web"
+	"x-bank-users/core/webhooks"
+	"x-bank-users/i18n"
+	"x-bank-users/infra/envelope"
 	"x-bank-users/infra/hasher"
+	"x-bank-users/infra/metrics"
 	"x-bank-users/infra/postgres"
 	"x-bank-users/infra/random"
 	"x-bank-users/infra/redis"
 	"x-bank-users/infra/telegram"
+	"x-bank-users/infra/webhook"
+	"x-bank-users/logging"
+	"x-bank-users/tracing"
+	"x-bank-users/transport/admin"
 	"x-bank-users/transport/http"
 	"x-bank-users/transport/http/jwt"
 )
 
 var (
-	addr       = flag.String("addr", ":8080", "")
-	configFile = flag.String("config", "config.json", "")
+	addr        = flag.String("addr", "", "")
+	adminAddr   = flag.String("admin-addr", "", "")
+	configFile  = flag.String("config", "config.json", "")
+	printConfig = flag.Bool("print-config", false, "")
+	overrides   config.Overrides
 )
 
 func main() {
+	flag.Var(&overrides, "set", "path=value")
 	flag.Parse()
-	conf, err := config.Read(*configFile)
+	if *addr != "" {
+		overrides = append(overrides, "addr="+*addr)
+	}
+	if *adminAddr != "" {
+		overrides = append(overrides, "admin.addr="+*adminAddr)
+	}
+
+	conf, err := config.Read(*configFile, overrides)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *printConfig {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(conf.Redacted()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	logLevel, err := logging.ParseLevel(conf.Log.Level)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var logLevelVar slog.LevelVar
+	logLevelVar.Set(logLevel)
+	logger := logging.New(os.Stdout, &logLevelVar)
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), conf.Tracing.Exporter, conf.Tracing.Endpoint, conf.Tracing.Insecure, conf.Tracing.ServiceName, conf.Tracing.SampleRatio)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	metricsService := metrics.NewService()
+
 	passwordHasher := hasher.NewService()
+	measuredPasswordHasher := metrics.NewPasswordHasher(&passwordHasher, &metricsService)
 
 	//jwtHs512, err := jwt.NewHS512(conf.Hs512SecretKey)
 	//if err != nil {
@@ -48,29 +100,157 @@ func main() {
 	}
 	randomGenerator := random.NewService()
 
-	postgresService, err := postgres.NewService(conf.Postgres.Login, conf.Postgres.Password, conf.Postgres.Host, conf.Postgres.Port, conf.Postgres.DataBase, conf.Postgres.MaxCons)
+	var kms envelope.LocalKMS
+	if conf.Encryption.KeyFile != "" {
+		kms, err = envelope.NewLocalKMSFromFile(conf.Encryption.KeyFile)
+	} else {
+		kms, err = envelope.NewLocalKMS(conf.Encryption.CurrentKeyId, conf.Encryption.MasterKeys)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	encryptionService, err := envelope.NewService(&kms, conf.Encryption.BlindIndexKey)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	postgresService, err := postgres.NewService(conf.Postgres.Login, conf.Postgres.Password, conf.Postgres.Host, conf.Postgres.Port, conf.Postgres.DataBase, conf.Postgres.MaxCons, conf.Postgres.IsolationLevel, conf.Postgres.TxMaxRetries, &encryptionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if conf.Postgres.AutoMigrate {
+		if _, err = postgresService.MigrateUp(logging.WithLogger(context.Background(), logger), 0); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if err = metricsService.RegisterDBStats("postgres", &postgresService); err != nil {
+		log.Fatal(err)
+	}
+	measuredUserStorage := metrics.NewUserStorage(&postgresService, "postgres", &metricsService)
+	measuredRefreshTokenStorage := metrics.NewRefreshTokenStorage(&redisService, "redis", &metricsService)
+
+	authSettings := web.AuthSettings{
+		HashCost:          conf.Auth.HashCost,
+		ClaimsTtl:         time.Duration(conf.Auth.ClaimsTtl),
+		RefreshTokenTtl:   time.Duration(conf.Auth.RefreshTokenTtl),
+		TwoFactorCodeTtl:  time.Duration(conf.Auth.TwoFactorCodeTtl),
+		RecoveryCodeTtl:   time.Duration(conf.Auth.RecoveryCodeTtl),
+		ActivationCodeTtl: time.Duration(conf.Auth.ActivationCodeTtl),
+	}
+
 	telegramService := telegram.NewService(conf.Telegram.BaseURL, conf.Telegram.Login, conf.Telegram.Password)
-	service := web.NewService(&postgresService, &randomGenerator, &redisService, &passwordHasher, &redisService, &redisService, &telegramService, &redisService)
+	measuredTelegramService := metrics.NewTwoFactorCodeNotifier(&telegramService, "telegram", &metricsService)
+	service := web.NewService(&measuredUserStorage, &randomGenerator, &redisService, &measuredPasswordHasher, &measuredRefreshTokenStorage, &redisService, &redisService, &measuredTelegramService, &redisService, &redisService, &postgresService, authSettings, web.RateLimits{ExportInterval: time.Duration(conf.RateLimits.ExportInterval)}, time.Duration(conf.AccountDeletionGracePeriod), &postgresService, &postgresService)
+
+	webhookService := webhook.NewService(conf.Outbox.WebhookURL)
+	webhooksService := webhooks.NewService(&postgresService, &postgresService, &postgresService, &webhookService, &randomGenerator)
+
+	publishers := outbox.Publishers{"webhooks": &webhooksService}
+	if conf.Outbox.WebhookURL != "" {
+		publishers["webhook"] = &webhookService
+	}
+
+	relayCtx, relayCancel := context.WithCancel(logging.WithLogger(context.Background(), logger.With(slog.String("component", "outbox"))))
+	defer relayCancel()
+	outboxService := outbox.NewService(&postgresService, publishers)
+	go outboxService.Run(relayCtx)
+
+	deliveryCtx, deliveryCancel := context.WithCancel(logging.WithLogger(context.Background(), logger.With(slog.String("component", "webhooks"))))
+	defer deliveryCancel()
+	go webhooksService.Run(deliveryCtx)
+
+	cleanerService := cleaner.NewService(&postgresService, &postgresService, nil, &metricsService, conf.Cleaner.BatchSize, conf.Cleaner.DryRun, 0)
+	schedulerService := scheduler.NewService(&postgresService, &postgresService, &metricsService, cleanerService.Jobs(conf.Scheduler.CleanExpiredUsers, conf.Scheduler.CleanDeletedUsers, ""))
+
+	schedulerCtx, schedulerCancel := context.WithCancel(logging.WithLogger(context.Background(), logger.With(slog.String("component", "scheduler"))))
+	defer schedulerCancel()
+	if conf.Scheduler.Enabled {
+		go func() {
+			if err := schedulerService.Run(schedulerCtx); err != nil {
+				logger.Error("scheduler stopped", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	cerrors.SetCaptureStack(conf.Debug)
+
+	catalog, err := i18n.NewCatalog(conf.DefaultLanguage)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	transport := http.NewTransport(service, &jwtRs256)
+	healthChecks := []health.Check{
+		{Name: "postgres", Pinger: &postgresService, Critical: true},
+		{Name: "redis", Pinger: &redisService, Critical: true},
+	}
+	if conf.Health.CheckTelegram {
+		healthChecks = append(healthChecks, health.Check{Name: "telegram", Pinger: &telegramService})
+	}
+	healthService := health.NewService(time.Duration(conf.Health.Timeout), time.Duration(conf.Health.CacheTtl), healthChecks)
 
-	errCh := transport.Start(*addr)
+	transport, err := http.NewTransport(service, webhooksService, &healthService, time.Duration(conf.Health.DrainDelay), &jwtRs256, conf.Internal.Login, conf.Internal.Password, conf.Cors.AllowedOrigins, conf.TrustedProxies, catalog, conf.Debug, logger, &metricsService)
+	if err != nil {
+		log.Fatal(err)
+	}
+	adminTransport, err := admin.NewTransport(metricsService.Handler(), &healthService, &schedulerService, conf.Admin.Login, conf.Admin.Password, conf.Admin.AllowedNetworks)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if conf.Admin.Login == "" && len(conf.Admin.AllowedNetworks) == 0 {
+		logger.Warn("admin server has neither basic auth nor allowed networks configured, all requests will be rejected")
+	}
+
+	errCh := transport.Start(conf.Addr)
+	adminErrCh := adminTransport.Start(conf.Admin.Addr)
 	interruptsCh := make(chan os.Signal, 1)
 	signal.Notify(interruptsCh, syscall.SIGINT, syscall.SIGTERM)
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
 
-	select {
-	case <-errCh:
-		log.Fatal(err)
-	case <-interruptsCh:
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer shutdownCancel()
-		err = transport.Stop(shutdownCtx)
-		if err != nil {
+	configReloader := reloader{
+		configFile: *configFile,
+		overrides:  overrides,
+		logLevel:   &logLevelVar,
+		authorizer: &jwtRs256,
+		service:    &service,
+		transport:  &transport,
+		telegram:   &telegramService,
+	}
+
+	for {
+		select {
+		case err = <-errCh:
+			log.Fatal(err)
+		case err = <-adminErrCh:
 			log.Fatal(err)
+		case <-reloadCh:
+			if err = configReloader.reload(); err != nil {
+				logger.Error("config reload rejected, keeping previous config", slog.String("error", err.Error()))
+				continue
+			}
+			logger.Info("config reloaded")
+		case <-interruptsCh:
+			relayCancel()
+			deliveryCancel()
+			schedulerCancel()
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer shutdownCancel()
+			err = transport.Stop(shutdownCtx)
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = adminTransport.Stop(shutdownCtx)
+			if err != nil {
+				log.Fatal(err)
+			}
+			err = shutdownTracing(shutdownCtx)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return
 		}
 	}
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+	"x-bank-users/config"
+	"x-bank-users/core/web"
+	"x-bank-users/infra/telegram"
+	"x-bank-users/logging"
+	"x-bank-users/transport/http"
+	"x-bank-users/transport/http/jwt"
+)
+
+type (
+	reloader struct {
+		configFile string
+		overrides  []string
+
+		logLevel   *slog.LevelVar
+		authorizer *jwt.RS256
+		service    *web.Service
+		transport  *http.Transport
+		telegram   *telegram.Service
+	}
+)
+
+func (r *reloader) reload() error {
+	conf, err := config.Read(r.configFile, r.overrides)
+	if err != nil {
+		return err
+	}
+
+	logLevel, err := logging.ParseLevel(conf.Log.Level)
+	if err != nil {
+		return err
+	}
+
+	if err = r.authorizer.Reload(conf.Rs256PrivateKey, conf.Rs256PublicKey); err != nil {
+		return err
+	}
+
+	r.logLevel.Set(logLevel)
+	r.service.SetRateLimits(web.RateLimits{ExportInterval: time.Duration(conf.RateLimits.ExportInterval)})
+	r.transport.SetCorsOrigins(conf.Cors.AllowedOrigins)
+	r.telegram.SetCredentials(conf.Telegram.Login, conf.Telegram.Password)
+
+	return nil
+}
@@ -2,21 +2,116 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
+	"time"
 )
 
 type (
 	Config struct {
-		Hs512SecretKey  string   `json:"hs512SecretKey"`
-		Rs256PrivateKey string   `json:"rs256PrivateKey"`
-		Rs256PublicKey  string   `json:"rs256PublicKey"`
-		Redis           Redis    `json:"redis"`
-		Postgres        Postgres `json:"postgres"`
-		Telegram        Telegram `json:"telegram"`
+		Addr            string     `json:"addr"`
+		Hs512SecretKey  string     `json:"hs512SecretKey" secret:"true"`
+		Rs256PrivateKey string     `json:"rs256PrivateKey"`
+		Rs256PublicKey  string     `json:"rs256PublicKey"`
+		Auth            Auth       `json:"auth"`
+		RateLimits      RateLimits `json:"rateLimits"`
+		Cors            Cors       `json:"cors"`
+		TrustedProxies  []string   `json:"trustedProxies"`
+		Redis           Redis      `json:"redis"`
+		Postgres        Postgres   `json:"postgres"`
+		Telegram        Telegram   `json:"telegram"`
+		Encryption      Encryption `json:"encryption"`
+		Internal        Internal   `json:"internal"`
+		Outbox          Outbox     `json:"outbox"`
+		Log             Log        `json:"log"`
+		Tracing         Tracing    `json:"tracing"`
+		Health          Health     `json:"health"`
+		Admin           Admin      `json:"admin"`
+		Scheduler       Scheduler  `json:"scheduler"`
+		Cleaner         Cleaner    `json:"cleaner"`
+
+		AccountDeletionGracePeriod Duration `json:"accountDeletionGracePeriod"`
+		DefaultLanguage            string   `json:"defaultLanguage"`
+		Debug                      bool     `json:"debug"`
+	}
+
+	Auth struct {
+		HashCost          int      `json:"hashCost"`
+		ClaimsTtl         Duration `json:"claimsTtl"`
+		RefreshTokenTtl   Duration `json:"refreshTokenTtl"`
+		TwoFactorCodeTtl  Duration `json:"twoFactorCodeTtl"`
+		RecoveryCodeTtl   Duration `json:"recoveryCodeTtl"`
+		ActivationCodeTtl Duration `json:"activationCodeTtl"`
+	}
+
+	RateLimits struct {
+		ExportInterval Duration `json:"exportInterval"`
+	}
+
+	Cors struct {
+		AllowedOrigins []string `json:"allowedOrigins"`
+	}
+
+	Log struct {
+		Level string `json:"level"`
+	}
+
+	Scheduler struct {
+		Enabled           bool   `json:"enabled"`
+		CleanExpiredUsers string `json:"cleanExpiredUsers"`
+		CleanDeletedUsers string `json:"cleanDeletedUsers"`
+	}
+
+	Cleaner struct {
+		BatchSize             int      `json:"batchSize"`
+		DryRun                bool     `json:"dryRun"`
+		AuthHistoryRetention  Duration `json:"authHistoryRetention"`
+		AuthHistoryArchiveDir string   `json:"authHistoryArchiveDir"`
+		PushgatewayURL        string   `json:"pushgatewayURL"`
+	}
+
+	Admin struct {
+		Addr            string   `json:"addr"`
+		Login           string   `json:"login"`
+		Password        string   `json:"password" secret:"true"`
+		AllowedNetworks []string `json:"allowedNetworks"`
+	}
+
+	Health struct {
+		Timeout       Duration `json:"timeout"`
+		CacheTtl      Duration `json:"cacheTtl"`
+		DrainDelay    Duration `json:"drainDelay"`
+		CheckTelegram bool     `json:"checkTelegram"`
+	}
+
+	Tracing struct {
+		Exporter    string  `json:"exporter"`
+		Endpoint    string  `json:"endpoint"`
+		Insecure    bool    `json:"insecure"`
+		ServiceName string  `json:"serviceName"`
+		SampleRatio float64 `json:"sampleRatio"`
+	}
+
+	Encryption struct {
+		CurrentKeyId  string            `json:"currentKeyId"`
+		MasterKeys    map[string]string `json:"masterKeys" secret:"true"`
+		KeyFile       string            `json:"keyFile"`
+		BlindIndexKey string            `json:"blindIndexKey" secret:"true"`
 	}
 
+	Internal struct {
+		Login    string `json:"login"`
+		Password string `json:"password" secret:"true"`
+	}
+
+	Outbox struct {
+		WebhookURL string `json:"webhookURL"`
+	}
+
+	Duration time.Duration
+
 	Redis struct {
-		Password string `json:"password"`
+		Password string `json:"password" secret:"true"`
 		Host     string `json:"host"`
 		Port     int    `json:"port"`
 		Database int    `json:"database"`
@@ -25,32 +120,108 @@ type (
 
 	Postgres struct {
 		Login    string `json:"login"`
-		Password string `json:"password"`
+		Password string `json:"password" secret:"true"`
 		Host     string `json:"host"`
 		Port     int    `json:"port"`
 		DataBase string `json:"dataBase"`
 		MaxCons  int    `json:"maxCons"`
+
+		IsolationLevel string `json:"isolationLevel"`
+		TxMaxRetries   int    `json:"txMaxRetries"`
+		AutoMigrate    bool   `json:"autoMigrate"`
 	}
 
 	Telegram struct {
 		BaseURL  string `json:"baseURL"`
 		Login    string `json:"login"`
-		Password string `json:"password"`
+		Password string `json:"password" secret:"true"`
 	}
 )
 
-func Read(filename string) (Config, error) {
-	f, err := os.Open(filename)
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+func Read(filename string, overrides []string) (Config, error) {
+	config, err := read(filename, overrides)
 	if err != nil {
 		return Config{}, err
 	}
-	defer func() { _ = f.Close() }()
 
-	var config Config
-	err = json.NewDecoder(f).Decode(&config)
+	if err = config.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+func ReadPostgres(filename string, overrides []string) (Postgres, error) {
+	config, err := read(filename, overrides)
+	if err != nil {
+		return Postgres{}, err
+	}
+
+	if err = config.Postgres.Validate(); err != nil {
+		return Postgres{}, err
+	}
+
+	return config.Postgres, nil
+}
+
+func ReadCleaner(filename string, overrides []string) (Config, error) {
+	config, err := read(filename, overrides)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err = config.ValidateCleaner(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+func read(filename string, overrides []string) (Config, error) {
+	config := Default()
+
+	if filename != "" {
+		if err := readFile(filename, &config); err != nil {
+			return Config{}, err
+		}
+	}
+
+	err := errors.Join(
+		applyEnv(&config, os.LookupEnv),
+		applyOverrides(&config, overrides),
+	)
 	if err != nil {
 		return Config{}, err
 	}
 
 	return config, nil
 }
+
+func readFile(filename string, config *Config) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	return json.NewDecoder(f).Decode(config)
+}
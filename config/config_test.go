@@ -0,0 +1,223 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestReadLayers(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      bool
+		env       bool
+		envFile   bool
+		overrides []string
+		want      string
+	}{
+		{name: "default", want: ""},
+		{name: "file", file: true, want: "from-file"},
+		{name: "env over file", file: true, env: true, want: "from-env"},
+		{name: "_FILE over env", file: true, env: true, envFile: true, want: "from-secret-file"},
+		{name: "flag over _FILE", file: true, env: true, envFile: true, overrides: []string{"postgres.password=from-flag"}, want: "from-flag"},
+		{name: "flag over file", file: true, overrides: []string{"postgres.password=from-flag"}, want: "from-flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filename string
+			if tt.file {
+				filename = writeFile(t, "config.json", `{"postgres": {"password": "from-file", "host": "db"}}`)
+			}
+			if tt.env {
+				t.Setenv("XBANK_POSTGRES_PASSWORD", "from-env")
+			}
+			if tt.envFile {
+				t.Setenv("XBANK_POSTGRES_PASSWORD_FILE", writeFile(t, "password", "from-secret-file\n"))
+			}
+
+			config, err := read(filename, tt.overrides)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if config.Postgres.Password != tt.want {
+				t.Fatalf("postgres.password = %q, want %q", config.Postgres.Password, tt.want)
+			}
+
+			wantHost := Default().Postgres.Host
+			if tt.file {
+				wantHost = "db"
+			}
+			if config.Postgres.Host != wantHost {
+				t.Fatalf("postgres.host = %q, want %q", config.Postgres.Host, wantHost)
+			}
+		})
+	}
+}
+
+func TestReadParsesTypedValues(t *testing.T) {
+	t.Setenv("XBANK_AUTH_CLAIMS_TTL", "90s")
+	t.Setenv("XBANK_CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("XBANK_ENCRYPTION_MASTER_KEYS", "k1=aa,k2=bb")
+
+	config, err := read("", []string{"auth.hashCost=12", "debug=true", "tracing.sampleRatio=0.5"})
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if got := time.Duration(config.Auth.ClaimsTtl); got != 90*time.Second {
+		t.Errorf("auth.claimsTtl = %s, want 90s", got)
+	}
+	if got, want := config.Cors.AllowedOrigins, []string{"https://a.example.com", "https://b.example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cors.allowedOrigins = %v, want %v", got, want)
+	}
+	if got, want := config.Encryption.MasterKeys, map[string]string{"k1": "aa", "k2": "bb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("encryption.masterKeys = %v, want %v", got, want)
+	}
+	if config.Auth.HashCost != 12 || !config.Debug || config.Tracing.SampleRatio != 0.5 {
+		t.Errorf("overrides not applied: hashCost=%d debug=%t sampleRatio=%v", config.Auth.HashCost, config.Debug, config.Tracing.SampleRatio)
+	}
+}
+
+func TestReadReportsEveryLayerError(t *testing.T) {
+	t.Setenv("XBANK_REDIS_PORT", "not-a-port")
+	t.Setenv("XBANK_TELEGRAM_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err := read("", []string{"postgres.maxCons=many", "unknown.field=1", "no-equals-sign"})
+	if err == nil {
+		t.Fatal("read: expected error")
+	}
+
+	for _, want := range []string{"XBANK_REDIS_PORT", "telegram.password", "postgres.maxCons", "unknown.field", "no-equals-sign"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateCollectsAllProblems(t *testing.T) {
+	config := Default()
+	config.Addr = ""
+	config.Auth.HashCost = 2
+	config.Redis.Port = 70000
+	config.Postgres.Login = ""
+	config.Log.Level = "loud"
+	config.Tracing.SampleRatio = 2
+	config.Scheduler.Enabled = true
+	config.Scheduler.CleanExpiredUsers = "every day"
+	config.TrustedProxies = []string{"10.0.0.0/8", "proxy"}
+
+	var verr *ValidationError
+	if err := config.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("Validate() = %v, want *ValidationError", err)
+	}
+
+	for _, path := range []string{
+		"addr:",
+		"rs256PrivateKey:",
+		"auth.hashCost:",
+		"redis.port:",
+		"postgres.login:",
+		"log.level:",
+		"tracing.sampleRatio:",
+		"scheduler.cleanExpiredUsers:",
+		"trustedProxies[1]:",
+		"encryption.blindIndexKey:",
+	} {
+		found := false
+		for _, problem := range verr.Problems {
+			if strings.HasPrefix(problem, path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("no problem reported for %s in %q", strings.TrimSuffix(path, ":"), verr.Problems)
+		}
+	}
+	for _, problem := range verr.Problems {
+		if strings.HasPrefix(problem, "trustedProxies[0]") {
+			t.Errorf("valid network reported: %s", problem)
+		}
+	}
+}
+
+func TestValidateCleanerChecksOnlyCleanerSections(t *testing.T) {
+	config := Default()
+	config.Postgres.Login = "cleaner"
+	config.Postgres.DataBase = "users"
+
+	if err := config.ValidateCleaner(); err != nil {
+		t.Fatalf("ValidateCleaner() = %v, want nil without web-only settings", err)
+	}
+
+	config.Cleaner.BatchSize = 0
+	config.Cleaner.PushgatewayURL = "pushgateway:9091"
+
+	var verr *ValidationError
+	if err := config.ValidateCleaner(); !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Fatalf("ValidateCleaner() = %v, want batchSize and pushgatewayURL problems", err)
+	}
+}
+
+func TestRedactedHidesEverySecret(t *testing.T) {
+	config := Default()
+
+	var secrets int
+	for _, f := range fields(&config) {
+		if !f.secret {
+			continue
+		}
+		secrets++
+
+		switch f.value.Kind() {
+		case reflect.String:
+			f.value.SetString("s3cret-" + f.path)
+		case reflect.Map:
+			f.value.Set(reflect.ValueOf(map[string]string{"k1": "s3cret-" + f.path}))
+		default:
+			t.Fatalf("%s: unsupported secret kind %s", f.path, f.value.Kind())
+		}
+	}
+	if secrets == 0 {
+		t.Fatal("no fields are tagged as secret")
+	}
+
+	redacted, err := json.Marshal(config.Redacted())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(redacted), "s3cret") {
+		t.Fatalf("redacted config leaks a secret: %s", redacted)
+	}
+	if got := strings.Count(string(redacted), redactedValue); got != secrets {
+		t.Fatalf("redacted %d values, want %d", got, secrets)
+	}
+
+	if config.Postgres.Password != "s3cret-postgres.password" || config.Encryption.MasterKeys["k1"] != "s3cret-encryption.masterKeys" {
+		t.Fatal("Redacted modified the original config")
+	}
+}
+
+func TestRedactedKeepsEmptySecretsEmpty(t *testing.T) {
+	config := Default()
+	config.Postgres.Password = ""
+
+	if got := config.Redacted().Postgres.Password; got != "" {
+		t.Fatalf("empty password redacted to %q", got)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import "time"
+
+func Default() Config {
+	return Config{
+		Addr: ":8080",
+		Auth: Auth{
+			HashCost:          10,
+			ClaimsTtl:         Duration(5 * time.Minute),
+			RefreshTokenTtl:   Duration(7 * 24 * time.Hour),
+			TwoFactorCodeTtl:  Duration(5 * time.Minute),
+			RecoveryCodeTtl:   Duration(5 * time.Minute),
+			ActivationCodeTtl: Duration(24 * time.Hour),
+		},
+		RateLimits: RateLimits{
+			ExportInterval: Duration(time.Hour),
+		},
+		Cors: Cors{
+			AllowedOrigins: []string{"*"},
+		},
+		Redis: Redis{
+			Host:    "localhost",
+			Port:    6379,
+			MaxCons: 10,
+		},
+		Postgres: Postgres{
+			Host:           "localhost",
+			Port:           5432,
+			MaxCons:        10,
+			IsolationLevel: "read committed",
+			TxMaxRetries:   3,
+		},
+		Log: Log{
+			Level: "info",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			ServiceName: "x-bank-users",
+			SampleRatio: 1,
+		},
+		Health: Health{
+			Timeout:    Duration(2 * time.Second),
+			CacheTtl:   Duration(time.Second),
+			DrainDelay: Duration(5 * time.Second),
+		},
+		Admin: Admin{
+			Addr:            ":9090",
+			AllowedNetworks: []string{"127.0.0.1/32", "::1/128"},
+		},
+		Scheduler: Scheduler{
+			CleanExpiredUsers: "@every 1h",
+			CleanDeletedUsers: "@every 10m",
+		},
+		Cleaner: Cleaner{
+			BatchSize: 500,
+		},
+		AccountDeletionGracePeriod: Duration(30 * 24 * time.Hour),
+		DefaultLanguage:            "ru",
+	}
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	envPrefix     = "XBANK_"
+	envFileSuffix = "_FILE"
+)
+
+var durationType = reflect.TypeOf(Duration(0))
+
+type (
+	field struct {
+		path   string
+		env    string
+		secret bool
+		value  reflect.Value
+	}
+
+	Overrides []string
+)
+
+func (o *Overrides) String() string {
+	return strings.Join(*o, ",")
+}
+
+func (o *Overrides) Set(value string) error {
+	*o = append(*o, value)
+	return nil
+}
+
+func fields(config *Config) []field {
+	var result []field
+	collectFields(reflect.ValueOf(config).Elem(), "", envPrefix[:len(envPrefix)-1], &result)
+	return result
+}
+
+func collectFields(v reflect.Value, path, env string, result *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		fieldEnv := env + "_" + envName(name)
+
+		if sf.Type.Kind() == reflect.Struct {
+			collectFields(v.Field(i), fieldPath, fieldEnv, result)
+			continue
+		}
+
+		*result = append(*result, field{
+			path:   fieldPath,
+			env:    fieldEnv,
+			secret: sf.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+}
+
+func envName(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func applyEnv(config *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, f := range fields(config) {
+		value, ok := lookup(f.env)
+		filename, fileOk := lookup(f.env + envFileSuffix)
+
+		switch {
+		case fileOk:
+			content, err := os.ReadFile(filename)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.path, err))
+				continue
+			}
+			value = strings.TrimRight(string(content), "\r\n")
+		case !ok:
+			continue
+		}
+
+		if err := setValue(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func applyOverrides(config *Config, overrides []string) error {
+	byPath := make(map[string]field)
+	for _, f := range fields(config) {
+		byPath[f.path] = f
+	}
+
+	var errs []error
+	for _, override := range overrides {
+		path, value, ok := strings.Cut(override, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("%q: ожидается формат путь=значение", override))
+			continue
+		}
+
+		f, ok := byPath[path]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: неизвестный параметр", path))
+			continue
+		}
+
+		if err := setValue(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(duration))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		items := make(map[string]string)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("%q: ожидается формат ключ=значение", item)
+			}
+			items[key] = value
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("неподдерживаемый тип %s", v.Type())
+	}
+
+	return nil
+}
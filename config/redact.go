@@ -0,0 +1,29 @@
+package config
+
+import (
+	"reflect"
+)
+
+const redactedValue = "[REDACTED]"
+
+func (c Config) Redacted() Config {
+	redacted := c
+	for _, f := range fields(&redacted) {
+		if !f.secret || f.value.IsZero() {
+			continue
+		}
+
+		switch f.value.Kind() {
+		case reflect.String:
+			f.value.SetString(redactedValue)
+		case reflect.Map:
+			items := make(map[string]string, f.value.Len())
+			for _, key := range f.value.MapKeys() {
+				items[key.String()] = redactedValue
+			}
+			f.value.Set(reflect.ValueOf(items))
+		}
+	}
+
+	return redacted
+}
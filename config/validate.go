@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	minHashCost = 4
+	maxHashCost = 31
+)
+
+type (
+	ValidationError struct {
+		Problems []string
+	}
+
+	validator struct {
+		problems []string
+	}
+)
+
+func (e *ValidationError) Error() string {
+	return "некорректная конфигурация:\n\t" + strings.Join(e.Problems, "\n\t")
+}
+
+func (v *validator) addf(path, format string, args ...any) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(path, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(path, "обязательный параметр")
+	}
+}
+
+func (v *validator) port(path string, value int) {
+	if value < 1 || value > 65535 {
+		v.addf(path, "порт должен быть в диапазоне 1-65535, получено %d", value)
+	}
+}
+
+func (v *validator) positive(path string, value int) {
+	if value <= 0 {
+		v.addf(path, "должно быть больше нуля, получено %d", value)
+	}
+}
+
+func (v *validator) positiveDuration(path string, value Duration) {
+	if value <= 0 {
+		v.addf(path, "должно быть больше нуля, получено %s", time.Duration(value))
+	}
+}
+
+func (v *validator) url(path, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		v.addf(path, "некорректный URL %q", value)
+	}
+}
+
+func (v *validator) oneOf(path, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.addf(path, "недопустимое значение %q, ожидается одно из: %s", value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) schedule(path, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := cron.ParseStandard(value); err != nil {
+		v.addf(path, "некорректное расписание %q: %v", value, err)
+	}
+}
+
+func (v *validator) postgres(c Postgres) {
+	v.required("postgres.login", c.Login)
+	v.required("postgres.host", c.Host)
+	v.port("postgres.port", c.Port)
+	v.required("postgres.dataBase", c.DataBase)
+	v.positive("postgres.maxCons", c.MaxCons)
+	v.oneOf("postgres.isolationLevel", strings.ToLower(c.IsolationLevel), "", "default", "read committed", "repeatable read", "serializable")
+	v.positive("postgres.txMaxRetries", c.TxMaxRetries)
+}
+
+func (v *validator) log(c Log) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		v.addf("log.level", "неизвестный уровень %q", c.Level)
+	}
+}
+
+func (v *validator) scheduler(c Scheduler) {
+	if c.Enabled {
+		v.schedule("scheduler.cleanExpiredUsers", c.CleanExpiredUsers)
+		v.schedule("scheduler.cleanDeletedUsers", c.CleanDeletedUsers)
+	}
+}
+
+func (v *validator) cleaner(c Cleaner) {
+	v.positive("cleaner.batchSize", c.BatchSize)
+	if c.AuthHistoryRetention < 0 {
+		v.addf("cleaner.authHistoryRetention", "не может быть отрицательным")
+	}
+	if c.AuthHistoryRetention > 0 {
+		v.required("cleaner.authHistoryArchiveDir", c.AuthHistoryArchiveDir)
+	}
+	v.url("cleaner.pushgatewayURL", c.PushgatewayURL)
+}
+
+func (v *validator) err() error {
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+func (c *Postgres) Validate() error {
+	v := &validator{}
+	v.postgres(*c)
+
+	return v.err()
+}
+
+func (c *Config) ValidateCleaner() error {
+	v := &validator{}
+
+	v.postgres(c.Postgres)
+	v.log(c.Log)
+	v.scheduler(c.Scheduler)
+	v.cleaner(c.Cleaner)
+
+	return v.err()
+}
+
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.required("addr", c.Addr)
+	v.required("rs256PrivateKey", c.Rs256PrivateKey)
+	v.required("rs256PublicKey", c.Rs256PublicKey)
+
+	if c.Auth.HashCost < minHashCost || c.Auth.HashCost > maxHashCost {
+		v.addf("auth.hashCost", "должно быть в диапазоне %d-%d, получено %d", minHashCost, maxHashCost, c.Auth.HashCost)
+	}
+	v.positiveDuration("auth.claimsTtl", c.Auth.ClaimsTtl)
+	v.positiveDuration("auth.refreshTokenTtl", c.Auth.RefreshTokenTtl)
+	v.positiveDuration("auth.twoFactorCodeTtl", c.Auth.TwoFactorCodeTtl)
+	v.positiveDuration("auth.recoveryCodeTtl", c.Auth.RecoveryCodeTtl)
+	v.positiveDuration("auth.activationCodeTtl", c.Auth.ActivationCodeTtl)
+
+	v.positiveDuration("rateLimits.exportInterval", c.RateLimits.ExportInterval)
+	for i, origin := range c.Cors.AllowedOrigins {
+		if origin != "*" {
+			v.url(fmt.Sprintf("cors.allowedOrigins[%d]", i), origin)
+		}
+	}
+
+	for i, cidr := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			v.addf(fmt.Sprintf("trustedProxies[%d]", i), "некорректная подсеть %q", cidr)
+		}
+	}
+
+	v.required("redis.host", c.Redis.Host)
+	v.port("redis.port", c.Redis.Port)
+	if c.Redis.Database < 0 {
+		v.addf("redis.database", "не может быть отрицательным")
+	}
+	v.positive("redis.maxCons", c.Redis.MaxCons)
+
+	v.postgres(c.Postgres)
+
+	v.required("telegram.baseURL", c.Telegram.BaseURL)
+	v.url("telegram.baseURL", c.Telegram.BaseURL)
+
+	if c.Encryption.KeyFile == "" {
+		v.required("encryption.currentKeyId", c.Encryption.CurrentKeyId)
+		if len(c.Encryption.MasterKeys) == 0 {
+			v.addf("encryption.masterKeys", "обязательный параметр, если не задан encryption.keyFile")
+		}
+	}
+	v.required("encryption.blindIndexKey", c.Encryption.BlindIndexKey)
+
+	if c.Internal.Login != "" {
+		v.required("internal.password", c.Internal.Password)
+	}
+
+	v.url("outbox.webhookURL", c.Outbox.WebhookURL)
+
+	v.log(c.Log)
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "", "none", "otlp", "stdout")
+	if c.Tracing.Exporter == "otlp" {
+		v.required("tracing.endpoint", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sampleRatio", "должно быть в диапазоне 0-1, получено %v", c.Tracing.SampleRatio)
+	}
+
+	v.positiveDuration("health.timeout", c.Health.Timeout)
+	if c.Health.CacheTtl < 0 {
+		v.addf("health.cacheTtl", "не может быть отрицательным")
+	}
+	if c.Health.DrainDelay < 0 {
+		v.addf("health.drainDelay", "не может быть отрицательным")
+	}
+
+	v.required("admin.addr", c.Admin.Addr)
+	if c.Admin.Login != "" {
+		v.required("admin.password", c.Admin.Password)
+	}
+	for i, cidr := range c.Admin.AllowedNetworks {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			v.addf(fmt.Sprintf("admin.allowedNetworks[%d]", i), "некорректная подсеть %q", cidr)
+		}
+	}
+
+	v.scheduler(c.Scheduler)
+	v.cleaner(c.Cleaner)
+
+	v.positiveDuration("accountDeletionGracePeriod", c.AccountDeletionGracePeriod)
+	v.oneOf("defaultLanguage", c.DefaultLanguage, "ru", "en")
+
+	return v.err()
+}
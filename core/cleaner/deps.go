@@ -7,6 +7,24 @@ import (
 
 type (
 	UserStorage interface {
-		DeleteUsersWithExpiredActivation(ctx context.Context, expirationTime time.Duration) error
+		DeleteUsersWithExpiredActivation(ctx context.Context, expirationTime time.Duration, batchSize int, dryRun bool) (int64, error)
+		DeleteUsersScheduledForDeletion(ctx context.Context, batchSize int, dryRun bool) (int64, error)
+	}
+
+	AuthHistoryStorage interface {
+		EnsureAuthHistoryPartitions(ctx context.Context, from, to time.Time) error
+		CountAuthHistoryBefore(ctx context.Context, before time.Time) (int64, error)
+		GetAuthHistoryBefore(ctx context.Context, before time.Time, limit int) ([]AuthHistoryRecord, error)
+		DeleteAuthHistory(ctx context.Context, ids []int64, before time.Time) error
+		DropAuthHistoryPartitions(ctx context.Context, before time.Time) ([]string, error)
+	}
+
+	AuthHistoryArchiver interface {
+		ArchiveAuthHistory(ctx context.Context, records []AuthHistoryRecord) error
+	}
+
+	Metrics interface {
+		ObserveDeletedUsers(job string, count int64)
+		ObserveArchivedAuthHistory(count int64)
 	}
 )
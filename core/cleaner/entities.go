@@ -0,0 +1,13 @@
+package cleaner
+
+import "time"
+
+type (
+	AuthHistoryRecord struct {
+		Id        int64     `json:"id"`
+		UserId    int64     `json:"userId"`
+		Agent     string    `json:"agent"`
+		Ip        string    `json:"ip"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+)
@@ -2,28 +2,158 @@ package cleaner
 
 import (
 	"context"
+	"log/slog"
 	"time"
+	"x-bank-users/core/scheduler"
+	"x-bank-users/logging"
 )
 
 type (
 	Service struct {
-		userStorage UserStorage
+		userStorage         UserStorage
+		authHistoryStorage  AuthHistoryStorage
+		authHistoryArchiver AuthHistoryArchiver
+		metrics             Metrics
+
+		batchSize            int
+		dryRun               bool
+		authHistoryRetention time.Duration
 	}
 )
 
-func NewService(userStorage UserStorage) Service {
+func NewService(userStorage UserStorage, authHistoryStorage AuthHistoryStorage, authHistoryArchiver AuthHistoryArchiver, metrics Metrics, batchSize int, dryRun bool, authHistoryRetention time.Duration) Service {
 	return Service{
-		userStorage: userStorage,
+		userStorage:          userStorage,
+		authHistoryStorage:   authHistoryStorage,
+		authHistoryArchiver:  authHistoryArchiver,
+		metrics:              metrics,
+		batchSize:            batchSize,
+		dryRun:               dryRun,
+		authHistoryRetention: authHistoryRetention,
 	}
 }
 
 const (
 	activationExpireTime = 24 * time.Hour
+
+	JobCleanExpiredUsers = "clean-expired-users"
+	JobCleanDeletedUsers = "clean-deleted-users"
+	JobRetainAuthHistory = "retain-auth-history"
+
+	authHistoryPartitionsAhead = 2
 )
 
+func (s *Service) Jobs(cleanExpiredUsersSchedule, cleanDeletedUsersSchedule, retainAuthHistorySchedule string) []scheduler.Job {
+	return []scheduler.Job{
+		{Name: JobCleanExpiredUsers, Schedule: cleanExpiredUsersSchedule, Run: s.CleanExpiredUsers},
+		{Name: JobCleanDeletedUsers, Schedule: cleanDeletedUsersSchedule, Run: s.CleanDeletedUsers},
+		{Name: JobRetainAuthHistory, Schedule: retainAuthHistorySchedule, Run: s.RetainAuthHistory},
+	}
+}
+
 func (s *Service) CleanExpiredUsers(ctx context.Context) error {
-	if err := s.userStorage.DeleteUsersWithExpiredActivation(ctx, activationExpireTime); err != nil {
+	count, err := s.userStorage.DeleteUsersWithExpiredActivation(ctx, activationExpireTime, s.batchSize, s.dryRun)
+	if s.dryRun {
+		if err == nil {
+			logging.FromContext(ctx).Info("dry run: users with expired activation would be deleted", slog.Int64("count", count))
+		}
 		return err
 	}
+
+	s.report(ctx, JobCleanExpiredUsers, count)
+	return err
+}
+
+func (s *Service) CleanDeletedUsers(ctx context.Context) error {
+	count, err := s.userStorage.DeleteUsersScheduledForDeletion(ctx, s.batchSize, s.dryRun)
+	if s.dryRun {
+		if err == nil {
+			logging.FromContext(ctx).Info("dry run: users scheduled for deletion would be deleted", slog.Int64("count", count))
+		}
+		return err
+	}
+
+	s.report(ctx, JobCleanDeletedUsers, count)
+	return err
+}
+
+func (s *Service) RetainAuthHistory(ctx context.Context) error {
+	logger := logging.FromContext(ctx)
+	now := time.Now()
+	before := now.Add(-s.authHistoryRetention)
+
+	if s.dryRun {
+		if s.authHistoryRetention == 0 {
+			return nil
+		}
+
+		count, err := s.authHistoryStorage.CountAuthHistoryBefore(ctx, before)
+		if err != nil {
+			return err
+		}
+		logger.Info("dry run: auth history records would be archived", slog.Int64("count", count), slog.Time("before", before))
+		return nil
+	}
+
+	if err := s.authHistoryStorage.EnsureAuthHistoryPartitions(ctx, now, now.AddDate(0, authHistoryPartitionsAhead, 0)); err != nil {
+		return err
+	}
+
+	if s.authHistoryRetention == 0 {
+		return nil
+	}
+
+	var archived int64
+	defer func() {
+		if archived > 0 {
+			logger.Info("auth history archived", slog.Int64("count", archived), slog.Time("before", before))
+			s.metrics.ObserveArchivedAuthHistory(archived)
+		}
+	}()
+
+	for {
+		records, err := s.authHistoryStorage.GetAuthHistoryBefore(ctx, before, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		if err = s.authHistoryArchiver.ArchiveAuthHistory(ctx, records); err != nil {
+			return err
+		}
+
+		ids := make([]int64, len(records))
+		for i, record := range records {
+			ids[i] = record.Id
+		}
+		if err = s.authHistoryStorage.DeleteAuthHistory(ctx, ids, before); err != nil {
+			return err
+		}
+
+		archived += int64(len(records))
+		if len(records) < s.batchSize {
+			break
+		}
+	}
+
+	dropped, err := s.authHistoryStorage.DropAuthHistoryPartitions(ctx, before)
+	if err != nil {
+		return err
+	}
+	for _, partition := range dropped {
+		logger.Info("auth history partition dropped", slog.String("partition", partition))
+	}
+
 	return nil
 }
+
+func (s *Service) report(ctx context.Context, job string, count int64) {
+	if count == 0 {
+		return
+	}
+
+	logging.FromContext(ctx).Info("users deleted", slog.Int64("count", count))
+	s.metrics.ObserveDeletedUsers(job, count)
+}
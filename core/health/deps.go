@@ -0,0 +1,9 @@
+package health
+
+import "context"
+
+type (
+	Pinger interface {
+		Ping(ctx context.Context) error
+	}
+)
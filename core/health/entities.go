@@ -0,0 +1,26 @@
+package health
+
+const (
+	StatusOk       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+type (
+	Check struct {
+		Name     string
+		Pinger   Pinger
+		Critical bool
+	}
+
+	CheckResult struct {
+		Status    string `json:"status"`
+		Critical  bool   `json:"critical"`
+		LatencyMs int64  `json:"latencyMs"`
+	}
+
+	Report struct {
+		Status string                 `json:"status"`
+		Checks map[string]CheckResult `json:"checks"`
+	}
+)
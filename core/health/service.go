@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+	"x-bank-users/logging"
+)
+
+const (
+	defaultCheckTimeout = 2 * time.Second
+)
+
+type (
+	Service struct {
+		checks   []Check
+		timeout  time.Duration
+		cacheTtl time.Duration
+
+		draining atomic.Bool
+
+		mu        sync.Mutex
+		checkedAt time.Time
+		report    Report
+	}
+)
+
+func NewService(timeout, cacheTtl time.Duration, checks []Check) Service {
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	return Service{
+		checks:   checks,
+		timeout:  timeout,
+		cacheTtl: cacheTtl,
+	}
+}
+
+func (s *Service) SetDraining() {
+	s.draining.Store(true)
+}
+
+func (s *Service) Ready(ctx context.Context) Report {
+	report := s.cachedReport(ctx)
+	if s.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func (s *Service) cachedReport(ctx context.Context) Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkedAt.IsZero() && time.Since(s.checkedAt) < s.cacheTtl {
+		return s.report
+	}
+
+	s.report = s.check(ctx)
+	s.checkedAt = time.Now()
+	return s.report
+}
+
+func (s *Service) check(ctx context.Context) Report {
+	results := make([]CheckResult, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.ping(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusOk,
+		Checks: make(map[string]CheckResult, len(s.checks)),
+	}
+	for i, check := range s.checks {
+		report.Checks[check.Name] = results[i]
+		if check.Critical && results[i].Status != StatusOk {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func (s *Service) ping(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Pinger.Ping(ctx)
+	result := CheckResult{
+		Status:    StatusOk,
+		Critical:  check.Critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		logging.FromContext(ctx).Warn("health check failed", slog.String("check", check.Name), slog.String("error", err.Error()))
+	}
+
+	return result
+}
@@ -0,0 +1,19 @@
+package outbox
+
+import (
+	"context"
+	"time"
+	"x-bank-users/entity"
+)
+
+type (
+	EventStorage interface {
+		ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]Record, error)
+		MarkOutboxEventPublished(ctx context.Context, id int64) error
+		MarkOutboxEventFailed(ctx context.Context, id int64, nextAttemptAt time.Time, reason string, publishedTo []string) error
+	}
+
+	EventPublisher interface {
+		Publish(ctx context.Context, event entity.Event) error
+	}
+)
@@ -0,0 +1,12 @@
+package outbox
+
+import "x-bank-users/entity"
+
+type (
+	Record struct {
+		Id          int64
+		Attempts    int
+		PublishedTo []string
+		Event       entity.Event
+	}
+)
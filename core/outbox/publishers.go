@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"x-bank-users/entity"
+)
+
+type (
+	Publishers map[string]EventPublisher
+)
+
+func (p Publishers) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p Publishers) PublishPending(ctx context.Context, event entity.Event, publishedTo []string) ([]string, map[string]error) {
+	done := make(map[string]bool, len(publishedTo))
+	for _, name := range publishedTo {
+		done[name] = true
+	}
+
+	errs := make(map[string]error)
+	for _, name := range p.names() {
+		if done[name] {
+			continue
+		}
+		if err := p[name].Publish(ctx, event); err != nil {
+			errs[name] = err
+			continue
+		}
+		publishedTo = append(publishedTo, name)
+	}
+
+	return publishedTo, errs
+}
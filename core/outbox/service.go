@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"x-bank-users/backoff"
+	"x-bank-users/logging"
+)
+
+type (
+	Service struct {
+		eventStorage EventStorage
+		publishers   Publishers
+	}
+)
+
+func NewService(eventStorage EventStorage, publishers Publishers) Service {
+	return Service{
+		eventStorage: eventStorage,
+		publishers:   publishers,
+	}
+}
+
+const (
+	pollInterval = time.Second
+	batchSize    = 100
+	claimLease   = time.Minute
+
+	publishTimeout = 15 * time.Second
+
+	retryBaseDelay = time.Second
+	retryMaxDelay  = time.Hour
+)
+
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			relayed, err := s.RelayBatch(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("outbox relay failed", slog.String("error", err.Error()))
+				break
+			}
+			if relayed < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) RelayBatch(ctx context.Context) (int, error) {
+	records, err := s.eventStorage.ClaimOutboxEvents(ctx, batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	leaseDeadline := time.Now().Add(claimLease - publishTimeout)
+	for i, record := range records {
+		if time.Now().After(leaseDeadline) {
+			return i, nil
+		}
+
+		publishCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+		publishedTo, errs := s.publishers.PublishPending(publishCtx, record.Event, record.PublishedTo)
+		cancel()
+
+		if len(errs) == 0 {
+			if err = s.eventStorage.MarkOutboxEventPublished(ctx, record.Id); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		reasons := make([]string, 0, len(errs))
+		for name, publishErr := range errs {
+			logging.FromContext(ctx).Warn("outbox event publish failed",
+				slog.String("eventId", record.Event.Id),
+				slog.String("eventType", record.Event.Type),
+				slog.String("publisher", name),
+				slog.Int("attempts", record.Attempts),
+				slog.String("error", publishErr.Error()),
+			)
+			reasons = append(reasons, name+": "+publishErr.Error())
+		}
+		sort.Strings(reasons)
+
+		nextAttemptAt := time.Now().Add(backoff.Jittered(record.Attempts, retryBaseDelay, retryMaxDelay))
+		if err = s.eventStorage.MarkOutboxEventFailed(ctx, record.Id, nextAttemptAt, strings.Join(reasons, "; "), publishedTo); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(records), nil
+}
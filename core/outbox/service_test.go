@@ -0,0 +1,232 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+	"x-bank-users/entity"
+	"x-bank-users/infra/memory"
+)
+
+type (
+	memoryRecord struct {
+		record        Record
+		nextAttemptAt time.Time
+		published     bool
+		lastError     string
+	}
+
+	memoryEventStorage struct {
+		mu      sync.Mutex
+		now     time.Time
+		records []*memoryRecord
+	}
+)
+
+func newMemoryEventStorage(events ...entity.Event) *memoryEventStorage {
+	storage := &memoryEventStorage{now: time.Now()}
+	for i, event := range events {
+		storage.records = append(storage.records, &memoryRecord{
+			record:        Record{Id: int64(i + 1), Event: event},
+			nextAttemptAt: storage.now,
+		})
+	}
+	return storage
+}
+
+func (s *memoryEventStorage) ClaimOutboxEvents(_ context.Context, limit int, lease time.Duration) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []Record
+	for _, r := range s.records {
+		if len(records) == limit {
+			break
+		}
+		if r.published || r.nextAttemptAt.After(s.now) {
+			continue
+		}
+		r.nextAttemptAt = s.now.Add(lease)
+		record := r.record
+		record.PublishedTo = append([]string(nil), r.record.PublishedTo...)
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (s *memoryEventStorage) MarkOutboxEventPublished(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.records[id-1]
+	r.published = true
+	r.record.Attempts++
+	r.lastError = ""
+	return nil
+}
+
+func (s *memoryEventStorage) MarkOutboxEventFailed(_ context.Context, id int64, nextAttemptAt time.Time, reason string, publishedTo []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.records[id-1]
+	r.record.Attempts++
+	r.record.PublishedTo = append([]string(nil), publishedTo...)
+	r.nextAttemptAt = nextAttemptAt
+	r.lastError = reason
+	return nil
+}
+
+func (s *memoryEventStorage) advanceTo(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.now = now
+}
+
+func (s *memoryEventStorage) get(id int64) memoryRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.records[id-1]
+}
+
+func testEvent(id string) entity.Event {
+	return entity.Event{Id: id, Type: entity.EventUserSignedUp, Version: 1, AggregateId: 7, Data: []byte(`{"userId":7}`)}
+}
+
+func eventIds(events []entity.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.Id)
+	}
+	return ids
+}
+
+func TestRelayPublishesToEveryPublisher(t *testing.T) {
+	storage := newMemoryEventStorage(testEvent("e1"), testEvent("e2"))
+	broker, webhooks := memory.NewService(), memory.NewService()
+	service := NewService(storage, Publishers{"broker": broker, "webhooks": webhooks})
+
+	relayed, err := service.RelayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+	if relayed != 2 {
+		t.Fatalf("relayed = %d, want 2", relayed)
+	}
+
+	for name, publisher := range map[string]*memory.Service{"broker": broker, "webhooks": webhooks} {
+		if got, want := eventIds(publisher.Events()), []string{"e1", "e2"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s received %v, want %v", name, got, want)
+		}
+	}
+	for id := int64(1); id <= 2; id++ {
+		if r := storage.get(id); !r.published || r.record.Attempts != 1 {
+			t.Errorf("record %d = %+v, want published after one attempt", id, r)
+		}
+	}
+
+	if relayed, err = service.RelayBatch(context.Background()); err != nil || relayed != 0 {
+		t.Fatalf("second RelayBatch = %d, %v, want nothing to relay", relayed, err)
+	}
+}
+
+func TestRelayRetriesOnlyFailedPublishers(t *testing.T) {
+	storage := newMemoryEventStorage(testEvent("e1"))
+	broker, webhooks := memory.NewService(), memory.NewService()
+	webhooks.SetError(errors.New("connection refused"))
+	service := NewService(storage, Publishers{"broker": broker, "webhooks": webhooks})
+	ctx := context.Background()
+
+	if _, err := service.RelayBatch(ctx); err != nil {
+		t.Fatalf("RelayBatch: %v", err)
+	}
+
+	failed := storage.get(1)
+	if failed.published {
+		t.Fatal("event marked published although a publisher failed")
+	}
+	if !reflect.DeepEqual(failed.record.PublishedTo, []string{"broker"}) {
+		t.Fatalf("publishedTo = %v, want [broker]", failed.record.PublishedTo)
+	}
+	if failed.lastError != "webhooks: connection refused" {
+		t.Fatalf("lastError = %q", failed.lastError)
+	}
+
+	webhooks.SetError(nil)
+	storage.advanceTo(failed.nextAttemptAt)
+	if _, err := service.RelayBatch(ctx); err != nil {
+		t.Fatalf("retry RelayBatch: %v", err)
+	}
+
+	if got := eventIds(broker.Events()); !reflect.DeepEqual(got, []string{"e1"}) {
+		t.Fatalf("broker received %v, want the event exactly once", got)
+	}
+	if got := eventIds(webhooks.Events()); !reflect.DeepEqual(got, []string{"e1"}) {
+		t.Fatalf("webhooks received %v, want the event after the retry", got)
+	}
+	if r := storage.get(1); !r.published || r.record.Attempts != 2 {
+		t.Fatalf("record = %+v, want published after two attempts", r)
+	}
+}
+
+func TestRelayBacksOffWithJitter(t *testing.T) {
+	storage := newMemoryEventStorage(testEvent("e1"))
+	publisher := memory.NewService()
+	publisher.SetError(errors.New("unavailable"))
+	service := NewService(storage, Publishers{"broker": publisher})
+	ctx := context.Background()
+
+	for attempt := 0; attempt < 4; attempt++ {
+		before := time.Now()
+		if _, err := service.RelayBatch(ctx); err != nil {
+			t.Fatalf("attempt %d: RelayBatch: %v", attempt, err)
+		}
+
+		r := storage.get(1)
+		if r.record.Attempts != attempt+1 {
+			t.Fatalf("attempt %d: attempts = %d", attempt, r.record.Attempts)
+		}
+		ceiling := retryBaseDelay << attempt
+		if delay := r.nextAttemptAt.Sub(before); delay < ceiling/2 || delay > ceiling+time.Second {
+			t.Fatalf("attempt %d: retry delay = %s, want within [%s, %s]", attempt, delay, ceiling/2, ceiling)
+		}
+
+		storage.advanceTo(r.nextAttemptAt.Add(-time.Millisecond))
+		if relayed, err := service.RelayBatch(ctx); err != nil || relayed != 0 {
+			t.Fatalf("attempt %d: relayed %d before the backoff elapsed, err %v", attempt, relayed, err)
+		}
+		storage.advanceTo(r.nextAttemptAt)
+	}
+}
+
+func TestRelayReclaimsEventsAfterLeaseExpiry(t *testing.T) {
+	storage := newMemoryEventStorage(testEvent("e1"))
+	publisher := memory.NewService()
+	service := NewService(storage, Publishers{"broker": publisher})
+	ctx := context.Background()
+
+	claimed, err := storage.ClaimOutboxEvents(ctx, batchSize, claimLease)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimOutboxEvents = %v, %v, want one record", claimed, err)
+	}
+
+	if relayed, err := service.RelayBatch(ctx); err != nil || relayed != 0 {
+		t.Fatalf("RelayBatch during lease = %d, %v, want nothing", relayed, err)
+	}
+	if len(publisher.Events()) != 0 {
+		t.Fatal("leased event was published by another relay")
+	}
+
+	storage.advanceTo(storage.now.Add(claimLease))
+	if relayed, err := service.RelayBatch(ctx); err != nil || relayed != 1 {
+		t.Fatalf("RelayBatch after lease expiry = %d, %v, want one", relayed, err)
+	}
+	if got := eventIds(publisher.Events()); !reflect.DeepEqual(got, []string{"e1"}) {
+		t.Fatalf("published %v, want [e1]", got)
+	}
+}
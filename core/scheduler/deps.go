@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	Locker interface {
+		TryLock(ctx context.Context, name string) (func(), bool, error)
+	}
+
+	RunStorage interface {
+		SaveJobRun(ctx context.Context, run JobRun) error
+		GetJobRun(ctx context.Context, name string) (JobRun, error)
+		GetJobRuns(ctx context.Context) ([]JobRun, error)
+	}
+
+	Metrics interface {
+		ObserveJobRun(job, status string, duration time.Duration)
+	}
+)
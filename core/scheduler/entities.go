@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+type (
+	Job struct {
+		Name     string
+		Schedule string
+		Run      func(ctx context.Context) error
+	}
+
+	JobRun struct {
+		Name       string    `json:"name"`
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+		Status     string    `json:"status"`
+		Error      string    `json:"error,omitempty"`
+		Runner     string    `json:"runner"`
+	}
+)
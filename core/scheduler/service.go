@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+	"x-bank-users/logging"
+)
+
+type (
+	alignedSchedule struct {
+		interval time.Duration
+	}
+
+	Service struct {
+		locker     Locker
+		runStorage RunStorage
+		metrics    Metrics
+
+		jobs   []Job
+		runner string
+	}
+)
+
+func NewService(locker Locker, runStorage RunStorage, metrics Metrics, jobs []Job) Service {
+	runner, err := os.Hostname()
+	if err != nil {
+		runner = "unknown"
+	}
+
+	return Service{
+		locker:     locker,
+		runStorage: runStorage,
+		metrics:    metrics,
+		jobs:       jobs,
+		runner:     runner,
+	}
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	schedules := make([]cron.Schedule, len(s.jobs))
+	for i, job := range s.jobs {
+		if job.Schedule == "" {
+			continue
+		}
+
+		schedule, err := parseSchedule(job.Schedule)
+		if err != nil {
+			return fmt.Errorf("задача %s: некорректное расписание %q: %w", job.Name, job.Schedule, err)
+		}
+		schedules[i] = schedule
+	}
+
+	var wg sync.WaitGroup
+	for i, job := range s.jobs {
+		if schedules[i] == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job, schedules[i])
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func parseSchedule(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if constant, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return alignedSchedule{interval: constant.Delay}, nil
+	}
+	return schedule, nil
+}
+
+func (s alignedSchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+func (s *Service) loop(ctx context.Context, job Job, schedule cron.Schedule) {
+	for {
+		scheduledAt := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(scheduledAt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		_, _ = s.runJob(ctx, job, scheduledAt)
+	}
+}
+
+func (s *Service) RunOnce(ctx context.Context, name string) (JobRun, error) {
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return s.RunJob(ctx, job)
+		}
+	}
+
+	return JobRun{}, fmt.Errorf("неизвестная задача %q", name)
+}
+
+func (s *Service) RunJob(ctx context.Context, job Job) (JobRun, error) {
+	return s.runJob(ctx, job, time.Time{})
+}
+
+func (s *Service) runJob(ctx context.Context, job Job, scheduledAt time.Time) (JobRun, error) {
+	logger := logging.FromContext(ctx).With(slog.String("job", job.Name))
+	ctx = logging.WithLogger(ctx, logger)
+
+	run := JobRun{
+		Name:      job.Name,
+		StartedAt: time.Now(),
+		Runner:    s.runner,
+	}
+
+	release, acquired, err := s.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		logger.Error("job lock failed", slog.String("error", err.Error()))
+		run.Status = StatusFailed
+		run.Error = err.Error()
+		run.FinishedAt = time.Now()
+		s.metrics.ObserveJobRun(job.Name, run.Status, run.FinishedAt.Sub(run.StartedAt))
+		return run, err
+	}
+	if !acquired {
+		logger.Debug("job is running on another replica")
+		run.Status = StatusSkipped
+		run.FinishedAt = time.Now()
+		s.metrics.ObserveJobRun(job.Name, run.Status, 0)
+		return run, nil
+	}
+	defer release()
+
+	if !scheduledAt.IsZero() {
+		lastRun, err := s.runStorage.GetJobRun(ctx, job.Name)
+		if err != nil {
+			logger.Error("job last run load failed", slog.String("error", err.Error()))
+			run.Status = StatusFailed
+			run.Error = err.Error()
+			run.FinishedAt = time.Now()
+			s.metrics.ObserveJobRun(job.Name, run.Status, run.FinishedAt.Sub(run.StartedAt))
+			return run, err
+		}
+		if !lastRun.StartedAt.Before(scheduledAt) {
+			logger.Debug("job already ran for this tick", slog.String("runner", lastRun.Runner), slog.Time("startedAt", lastRun.StartedAt))
+			run.Status = StatusSkipped
+			run.FinishedAt = time.Now()
+			s.metrics.ObserveJobRun(job.Name, run.Status, 0)
+			return run, nil
+		}
+	}
+
+	err = job.Run(ctx)
+
+	run.FinishedAt = time.Now()
+	run.Status = StatusSuccess
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error = err.Error()
+		logger.Error("job failed", slog.String("error", err.Error()), slog.Duration("duration", run.FinishedAt.Sub(run.StartedAt)))
+	} else {
+		logger.Info("job finished", slog.Duration("duration", run.FinishedAt.Sub(run.StartedAt)))
+	}
+
+	s.metrics.ObserveJobRun(job.Name, run.Status, run.FinishedAt.Sub(run.StartedAt))
+	if saveErr := s.runStorage.SaveJobRun(context.WithoutCancel(ctx), run); saveErr != nil {
+		logger.Error("job run status save failed", slog.String("error", saveErr.Error()))
+	}
+
+	return run, err
+}
+
+func (s *Service) GetJobRuns(ctx context.Context) ([]JobRun, error) {
+	return s.runStorage.GetJobRuns(ctx)
+}
@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	memoryStore struct {
+		mu     sync.Mutex
+		locked map[string]bool
+		runs   map[string]JobRun
+	}
+
+	noopMetrics struct{}
+)
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		locked: make(map[string]bool),
+		runs:   make(map[string]JobRun),
+	}
+}
+
+func (m *memoryStore) TryLock(_ context.Context, name string) (func(), bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.locked[name] {
+		return nil, false, nil
+	}
+	m.locked[name] = true
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(m.locked, name)
+	}, true, nil
+}
+
+func (m *memoryStore) SaveJobRun(_ context.Context, run JobRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs[run.Name] = run
+	return nil
+}
+
+func (m *memoryStore) GetJobRun(_ context.Context, name string) (JobRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.runs[name], nil
+}
+
+func (m *memoryStore) GetJobRuns(_ context.Context) ([]JobRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runs := make([]JobRun, 0, len(m.runs))
+	for _, run := range m.runs {
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func (noopMetrics) ObserveJobRun(string, string, time.Duration) {}
+
+func TestIntervalTicksAreSharedBetweenReplicas(t *testing.T) {
+	schedule, err := parseSchedule("@every 10m")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+
+	base := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	first := schedule.Next(base.Add(3*time.Minute + 17*time.Second))
+	second := schedule.Next(base.Add(8*time.Minute + 2*time.Second))
+
+	if want := base.Add(10 * time.Minute); !first.Equal(want) || !second.Equal(want) {
+		t.Fatalf("ticks = %s, %s, want both %s", first, second, want)
+	}
+}
+
+func TestJobRunsOncePerTickAcrossReplicas(t *testing.T) {
+	store := newMemoryStore()
+
+	var mu sync.Mutex
+	runs := 0
+	job := Job{Name: "clean", Schedule: "@every 10m", Run: func(context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs++
+		return nil
+	}}
+
+	replicas := []Service{
+		NewService(store, store, noopMetrics{}, []Job{job}),
+		NewService(store, store, noopMetrics{}, []Job{job}),
+	}
+
+	schedule, err := parseSchedule(job.Schedule)
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+	tick := schedule.Next(time.Now().Add(-10 * time.Minute))
+
+	for i := range replicas {
+		run, err := replicas[i].runJob(context.Background(), job, tick)
+		if err != nil {
+			t.Fatalf("replica %d: %v", i, err)
+		}
+		if want := []string{StatusSuccess, StatusSkipped}[i]; run.Status != want {
+			t.Fatalf("replica %d status = %s, want %s", i, run.Status, want)
+		}
+	}
+	if runs != 1 {
+		t.Fatalf("job ran %d times for one tick, want 1", runs)
+	}
+
+	if _, err = replicas[1].runJob(context.Background(), job, schedule.Next(tick)); err != nil {
+		t.Fatalf("next tick: %v", err)
+	}
+	if runs != 2 {
+		t.Fatalf("job ran %d times for two ticks, want 2", runs)
+	}
+}
+
+func TestCronTicksAreUnchanged(t *testing.T) {
+	schedule, err := parseSchedule("30 3 * * *")
+	if err != nil {
+		t.Fatalf("parseSchedule: %v", err)
+	}
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	if got, want := schedule.Next(now), time.Date(2026, 10, 20, 3, 30, 0, 0, time.Local); !got.Equal(want) {
+		t.Fatalf("next = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"x-bank-users/auth"
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+)
+
+type (
+	accountStorage struct {
+		UserStorage
+
+		user       UserDataToSignIn
+		deleteAt   *time.Time
+		cancelled  []int64
+		authEvents int
+	}
+
+	plainPasswordHasher struct {
+		PasswordHasher
+	}
+
+	fixedRandomGenerator struct {
+		RandomGenerator
+	}
+
+	accountSessions struct {
+		RefreshTokenStorage
+
+		expired []int64
+		saved   []string
+	}
+
+	memoryAccessTokenRevoker struct {
+		revokedAt map[int64]int64
+		ttl       time.Duration
+	}
+)
+
+func (s *accountStorage) GetSignInDataByLogin(context.Context, string) (UserDataToSignIn, error) {
+	return s.user, nil
+}
+
+func (s *accountStorage) GetSignInDataById(context.Context, int64) (UserDataToSignIn, error) {
+	return s.user, nil
+}
+
+func (s *accountStorage) AddUsersAuthHistory(context.Context, int64, string, string) error {
+	s.authEvents++
+	return nil
+}
+
+func (s *accountStorage) ScheduleUserDeletion(_ context.Context, _ int64, deleteAt time.Time) error {
+	s.deleteAt = &deleteAt
+	return nil
+}
+
+func (s *accountStorage) CancelUserDeletion(_ context.Context, userId int64) error {
+	s.cancelled = append(s.cancelled, userId)
+	s.deleteAt = nil
+	return nil
+}
+
+func (plainPasswordHasher) CompareHashAndPassword(_ context.Context, password string, hashedPassword []byte) error {
+	if password != string(hashedPassword) {
+		return cerrors.NewErrorWithUserMessage(ercodes.WrongPassword, nil, "Неверный логин или пароль").WithKind(cerrors.KindUnauthenticated)
+	}
+	return nil
+}
+
+func (fixedRandomGenerator) GenerateString(context.Context, string, int) (string, error) {
+	return "refresh-token", nil
+}
+
+func (s *accountSessions) ExpireAllByUserId(_ context.Context, userId int64) error {
+	s.expired = append(s.expired, userId)
+	return nil
+}
+
+func (s *accountSessions) SaveRefreshToken(_ context.Context, token string, _ int64, _ time.Duration) error {
+	s.saved = append(s.saved, token)
+	return nil
+}
+
+func (r *memoryAccessTokenRevoker) RevokeAccessTokens(_ context.Context, userId int64, issuedBefore time.Time, ttl time.Duration) error {
+	r.revokedAt[userId] = issuedBefore.Unix()
+	r.ttl = ttl
+	return nil
+}
+
+func (r *memoryAccessTokenRevoker) AccessTokensRevokedAt(_ context.Context, userId int64) (int64, error) {
+	return r.revokedAt[userId], nil
+}
+
+func newAccountService(storage *accountStorage) (Service, *accountSessions, *memoryAccessTokenRevoker, *memoryAuditLogger) {
+	sessions := &accountSessions{}
+	revoker := &memoryAccessTokenRevoker{revokedAt: map[int64]int64{}}
+	auditLogger := &memoryAuditLogger{}
+	service := Service{
+		userStorage:                storage,
+		randomGenerator:            fixedRandomGenerator{},
+		passwordHasher:             plainPasswordHasher{},
+		refreshTokenStorage:        sessions,
+		accessTokenRevoker:         revoker,
+		auditLogger:                auditLogger,
+		transactor:                 passthroughTransactor{},
+		authSettings:               AuthSettings{ClaimsTtl: 15 * time.Minute, RefreshTokenTtl: time.Hour},
+		accountDeletionGracePeriod: 30 * 24 * time.Hour,
+	}
+
+	return service, sessions, revoker, auditLogger
+}
+
+func TestDeleteAccountSchedulesDeletionAndRevokesTokens(t *testing.T) {
+	storage := &accountStorage{user: UserDataToSignIn{Id: 7, PasswordHash: []byte("secret")}}
+	service, sessions, revoker, auditLogger := newAccountService(storage)
+
+	before := time.Now()
+	deleteAt, err := service.DeleteAccount(context.Background(), 7, "secret")
+	if err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+
+	if deleteAt.Before(before.Add(service.accountDeletionGracePeriod)) || deleteAt.After(time.Now().Add(service.accountDeletionGracePeriod)) {
+		t.Fatalf("deleteAt = %s, want now + %s", deleteAt, service.accountDeletionGracePeriod)
+	}
+	if storage.deleteAt == nil || !storage.deleteAt.Equal(deleteAt) {
+		t.Fatalf("scheduled deleteAt = %v, want %s", storage.deleteAt, deleteAt)
+	}
+	if len(auditLogger.events) != 1 || auditLogger.events[0].Action != AuditActionDeletionScheduled {
+		t.Fatalf("audit events = %+v, want one %s", auditLogger.events, AuditActionDeletionScheduled)
+	}
+	if len(sessions.expired) != 1 || sessions.expired[0] != 7 {
+		t.Fatalf("expired sessions = %v, want [7]", sessions.expired)
+	}
+	if revoker.revokedAt[7] < before.Unix() || revoker.ttl != service.authSettings.ClaimsTtl {
+		t.Fatalf("revocation = %d with ttl %s, want now with ttl %s", revoker.revokedAt[7], revoker.ttl, service.authSettings.ClaimsTtl)
+	}
+}
+
+func TestDeleteAccountRequiresPassword(t *testing.T) {
+	storage := &accountStorage{user: UserDataToSignIn{Id: 7, PasswordHash: []byte("secret")}}
+	service, sessions, revoker, auditLogger := newAccountService(storage)
+
+	_, err := service.DeleteAccount(context.Background(), 7, "wrong")
+
+	var cerr *cerrors.Error
+	if !errors.As(err, &cerr) || cerr.Code != ercodes.WrongPassword {
+		t.Fatalf("DeleteAccount = %v, want WrongPassword", err)
+	}
+	if storage.deleteAt != nil || len(auditLogger.events) != 0 || len(sessions.expired) != 0 || len(revoker.revokedAt) != 0 {
+		t.Fatal("DeleteAccount with a wrong password changed state")
+	}
+}
+
+func TestSignInCancelsScheduledDeletion(t *testing.T) {
+	deleteAt := time.Now().Add(time.Hour)
+	storage := &accountStorage{user: UserDataToSignIn{Id: 7, PasswordHash: []byte("secret"), DeleteAt: &deleteAt}}
+	service, sessions, _, auditLogger := newAccountService(storage)
+
+	result, err := service.SignIn(context.Background(), "ivan", "secret", "curl/8.0", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+
+	if result.RefreshToken != "refresh-token" || len(sessions.saved) != 1 {
+		t.Fatalf("refresh token = %q, saved %v", result.RefreshToken, sessions.saved)
+	}
+	if len(storage.cancelled) != 1 || storage.cancelled[0] != 7 || storage.deleteAt != nil {
+		t.Fatalf("cancelled = %v, deleteAt = %v, want deletion of 7 cancelled", storage.cancelled, storage.deleteAt)
+	}
+	if len(auditLogger.events) != 1 || auditLogger.events[0].Action != AuditActionDeletionCancelled {
+		t.Fatalf("audit events = %+v, want one %s", auditLogger.events, AuditActionDeletionCancelled)
+	}
+}
+
+func TestSignInWithoutScheduledDeletionCancelsNothing(t *testing.T) {
+	storage := &accountStorage{user: UserDataToSignIn{Id: 7, PasswordHash: []byte("secret")}}
+	service, _, _, auditLogger := newAccountService(storage)
+
+	if _, err := service.SignIn(context.Background(), "ivan", "secret", "curl/8.0", "10.0.0.1"); err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	if len(storage.cancelled) != 0 || len(auditLogger.events) != 0 {
+		t.Fatalf("cancelled = %v, audit events = %+v, want none", storage.cancelled, auditLogger.events)
+	}
+}
+
+func TestVerifyAccessClaimsRejectsRevokedTokens(t *testing.T) {
+	service, _, revoker, _ := newAccountService(&accountStorage{})
+	ctx := context.Background()
+	now := time.Now().Unix()
+
+	if err := service.VerifyAccessClaims(ctx, auth.Claims{Sub: 7, IssuedAt: now}); err != nil {
+		t.Fatalf("VerifyAccessClaims without revocation: %v", err)
+	}
+
+	revoker.revokedAt[7] = now
+	for _, tt := range []struct {
+		name     string
+		issuedAt int64
+		revoked  bool
+	}{
+		{"issued before revocation", now - 60, true},
+		{"issued at revocation", now, true},
+		{"issued after revocation", now + 1, false},
+	} {
+		err := service.VerifyAccessClaims(ctx, auth.Claims{Sub: 7, IssuedAt: tt.issuedAt})
+		if !tt.revoked {
+			if err != nil {
+				t.Errorf("%s: VerifyAccessClaims = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+
+		var cerr *cerrors.Error
+		if !errors.As(err, &cerr) || cerr.Code != ercodes.AccessTokenRevoked || cerr.Kind != cerrors.KindUnauthenticated {
+			t.Errorf("%s: VerifyAccessClaims = %v, want unauthenticated AccessTokenRevoked", tt.name, err)
+		}
+	}
+
+	if err := service.VerifyAccessClaims(ctx, auth.Claims{Sub: 8, IssuedAt: now - 60}); err != nil {
+		t.Fatalf("VerifyAccessClaims for another user: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package web
+
+import (
+	"strconv"
+	"time"
+	"x-bank-users/entity"
+)
+
+const (
+	maskedValue = "***"
+)
+
+var (
+	sensitiveAuditFields = map[string]bool{
+		"password":    true,
+		"phoneNumber": true,
+		"dateOfBirth": true,
+		"passportId":  true,
+		"address":     true,
+	}
+)
+
+func personalDataValues(data entity.UserPersonalData) map[string]string {
+	return map[string]string{
+		"phoneNumber":   data.PhoneNumber,
+		"firstName":     data.FirstName,
+		"lastName":      data.LastName,
+		"fathersName":   stringOrEmpty(data.FathersName),
+		"dateOfBirth":   data.DateOfBirth,
+		"passportId":    data.PassportId,
+		"address":       data.Address,
+		"gender":        data.Gender,
+		"liveInCountry": strconv.FormatInt(data.LiveInCountryId, 10),
+	}
+}
+
+func storedPersonalDataValues(data *UserPersonalData) map[string]string {
+	if data == nil {
+		return nil
+	}
+
+	return personalDataValues(entity.UserPersonalData{
+		PhoneNumber:     data.PhoneNumber,
+		FirstName:       data.FirstName,
+		LastName:        data.LastName,
+		FathersName:     data.FathersName,
+		DateOfBirth:     data.DateOfBirth.Format(time.DateOnly),
+		PassportId:      data.PassportId,
+		Address:         data.Address,
+		Gender:          data.Gender,
+		LiveInCountryId: data.LiveInCountryId,
+	})
+}
+
+func workplaceValues(id int64, work entity.Workplace) map[string]string {
+	return map[string]string{
+		"id":             strconv.FormatInt(id, 10),
+		"companyName":    work.CompanyName,
+		"companyAddress": work.CompanyAddress,
+		"position":       work.Position,
+		"startDate":      work.StartDate,
+		"endDate":        stringOrEmpty(work.EndDate),
+	}
+}
+
+func storedWorkplaceValues(work entity.UserWorkplace) map[string]string {
+	var endDate *string
+	if work.EndDate != nil {
+		end := time.Unix(*work.EndDate, 0).UTC().Format(time.DateOnly)
+		endDate = &end
+	}
+
+	return workplaceValues(work.Id, entity.Workplace{
+		CompanyName:    work.CompanyName,
+		CompanyAddress: work.CompanyAddress,
+		Position:       work.Position,
+		StartDate:      time.Unix(work.StartDate, 0).UTC().Format(time.DateOnly),
+		EndDate:        endDate,
+	})
+}
+
+func auditDiff(before, after map[string]string) (map[string]string, map[string]string) {
+	changedBefore := make(map[string]string)
+	changedAfter := make(map[string]string)
+
+	for field, value := range after {
+		if old, ok := before[field]; ok && old == value {
+			continue
+		}
+		if old, ok := before[field]; ok {
+			changedBefore[field] = maskAuditValue(field, old)
+		}
+		changedAfter[field] = maskAuditValue(field, value)
+	}
+
+	return changedBefore, changedAfter
+}
+
+func maskAuditValue(field, value string) string {
+	if !sensitiveAuditFields[field] {
+		return value
+	}
+
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return maskedValue
+	}
+	return maskedValue + string(runes[len(runes)-2:])
+}
+
+func formatAuditTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func int64OrEmpty(i *int64) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatInt(*i, 10)
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,143 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"x-bank-users/entity"
+)
+
+type (
+	auditedStorage struct {
+		UserStorage
+
+		personalData *UserPersonalData
+		workplaces   []entity.UserWorkplace
+		err          error
+	}
+)
+
+func (s *auditedStorage) LockUserById(context.Context, int64) error {
+	return nil
+}
+
+func (s *auditedStorage) GetUserPersonalDataById(context.Context, int64) (*UserPersonalData, error) {
+	return s.personalData, nil
+}
+
+func (s *auditedStorage) UpdateUserPersonalDataById(context.Context, int64, entity.UserPersonalData) error {
+	return s.err
+}
+
+func (s *auditedStorage) GetUserWorkplaces(context.Context, int64) ([]entity.UserWorkplace, error) {
+	return s.workplaces, nil
+}
+
+func (s *auditedStorage) UpdateUserWorkplace(context.Context, int64, int64, entity.Workplace) error {
+	return s.err
+}
+
+func (s *auditedStorage) DeleteUserWorkplace(context.Context, int64, int64) error {
+	return s.err
+}
+
+func newAuditedService(storage *auditedStorage) (Service, *memoryAuditLogger) {
+	auditLogger := &memoryAuditLogger{}
+	return Service{
+		userStorage: storage,
+		transactor:  passthroughTransactor{},
+		auditLogger: auditLogger,
+	}, auditLogger
+}
+
+func TestUpdateWorkplaceAuditsChangedFields(t *testing.T) {
+	endDate := int64(1704067200)
+	storage := &auditedStorage{workplaces: []entity.UserWorkplace{{
+		Id:             7,
+		CompanyName:    "Bank",
+		CompanyAddress: "Moscow",
+		Position:       "Analyst",
+		StartDate:      1672531200,
+		EndDate:        &endDate,
+	}}}
+	service, auditLogger := newAuditedService(storage)
+
+	err := service.UpdateWorkplace(context.Background(), 1, 7, entity.Workplace{
+		CompanyName:    "Bank",
+		CompanyAddress: "Moscow",
+		Position:       "Lead",
+		StartDate:      "2023-01-01",
+		EndDate:        nil,
+	})
+	if err != nil {
+		t.Fatalf("UpdateWorkplace: %v", err)
+	}
+
+	if len(auditLogger.events) != 1 {
+		t.Fatalf("audit events = %d, want 1", len(auditLogger.events))
+	}
+	event := auditLogger.events[0]
+	if event.Action != AuditActionWorkplaceUpdated || event.SubjectId != 1 {
+		t.Fatalf("event = %s for %d", event.Action, event.SubjectId)
+	}
+	wantBefore := map[string]string{"position": "Analyst", "endDate": "2024-01-01"}
+	wantAfter := map[string]string{"position": "Lead", "endDate": ""}
+	for field, want := range wantBefore {
+		if got := event.Before[field]; got != want {
+			t.Errorf("before[%s] = %q, want %q", field, got, want)
+		}
+	}
+	for field, want := range wantAfter {
+		if got, ok := event.After[field]; !ok || got != want {
+			t.Errorf("after[%s] = %q, want %q", field, got, want)
+		}
+	}
+	if len(event.After) != len(wantAfter) {
+		t.Errorf("after = %v, want only changed fields", event.After)
+	}
+}
+
+func TestPersonalDataAuditMasksSensitiveFields(t *testing.T) {
+	storage := &auditedStorage{personalData: &UserPersonalData{
+		PhoneNumber:     "+79990001122",
+		FirstName:       "Ivan",
+		LiveInCountryId: 1,
+	}}
+	service, auditLogger := newAuditedService(storage)
+	service.countries = newCountryCache(staticCountries{{Id: 1}})
+
+	err := service.AddUserPersonalData(context.Background(), 1, entity.UserPersonalData{
+		PhoneNumber:     "+79990003344",
+		FirstName:       "Ivan",
+		LiveInCountryId: 1,
+	})
+	if err != nil {
+		t.Fatalf("AddUserPersonalData: %v", err)
+	}
+
+	event := auditLogger.events[0]
+	if event.Action != AuditActionPersonalDataUpdated {
+		t.Fatalf("action = %s, want %s", event.Action, AuditActionPersonalDataUpdated)
+	}
+	if got := event.Before["phoneNumber"]; got != maskedValue+"22" {
+		t.Errorf("before phoneNumber = %q", got)
+	}
+	if got := event.After["phoneNumber"]; got != maskedValue+"44" {
+		t.Errorf("after phoneNumber = %q", got)
+	}
+	if _, ok := event.After["firstName"]; ok {
+		t.Errorf("unchanged firstName is audited: %v", event.After)
+	}
+}
+
+func TestFailedChangeIsNotAudited(t *testing.T) {
+	storage := &auditedStorage{err: errors.New("not found")}
+	service, auditLogger := newAuditedService(storage)
+
+	if err := service.DeleteWorkplace(context.Background(), 1, 7); err == nil {
+		t.Fatal("DeleteWorkplace succeeded")
+	}
+	if len(auditLogger.events) != 0 {
+		t.Fatalf("audit events = %v, want none", auditLogger.events)
+	}
+}
@@ -0,0 +1,16 @@
+package web
+
+import "context"
+
+type (
+	requestMetaCtxKey struct{}
+)
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaCtxKey{}, meta)
+}
+
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaCtxKey{}).(RequestMeta)
+	return meta
+}
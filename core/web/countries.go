@@ -0,0 +1,114 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+	"x-bank-users/cerrors"
+	"x-bank-users/entity"
+	"x-bank-users/ercodes"
+	"x-bank-users/tracing"
+)
+
+const (
+	countriesCacheTtl = time.Hour
+)
+
+type countryCache struct {
+	storage CountryStorage
+
+	mu       sync.RWMutex
+	loadedAt time.Time
+	list     Countries
+	byId     map[int64]entity.Country
+}
+
+func newCountryCache(storage CountryStorage) *countryCache {
+	return &countryCache{storage: storage}
+}
+
+func (c *countryCache) all(ctx context.Context) (Countries, error) {
+	c.mu.RLock()
+	if !c.loadedAt.IsZero() && time.Since(c.loadedAt) < countriesCacheTtl {
+		list := c.list
+		c.mu.RUnlock()
+		return list, nil
+	}
+	c.mu.RUnlock()
+
+	return c.reload(ctx)
+}
+
+func (c *countryCache) get(ctx context.Context, id int64) (entity.Country, error) {
+	if _, err := c.all(ctx); err != nil {
+		return entity.Country{}, err
+	}
+
+	c.mu.RLock()
+	country, ok := c.byId[id]
+	c.mu.RUnlock()
+
+	if !ok {
+		return entity.Country{}, cerrors.NewErrorWithUserMessage(ercodes.CountryNotFound, nil, "Неизвестная страна проживания")
+	}
+	return country, nil
+}
+
+func (c *countryCache) reload(ctx context.Context) (Countries, error) {
+	items, err := c.storage.GetCountries(ctx)
+	if err != nil {
+		return Countries{}, err
+	}
+
+	hash := sha256.New()
+	byId := make(map[int64]entity.Country, len(items))
+	for _, country := range items {
+		byId[country.Id] = country
+		hash.Write([]byte(strconv.FormatInt(country.Id, 10)))
+		for _, field := range []string{country.Alpha2, country.Alpha3, country.NameRu, country.NameEn, country.PhonePrefix} {
+			hash.Write([]byte{0})
+			hash.Write([]byte(field))
+		}
+		hash.Write([]byte{'\n'})
+	}
+
+	list := Countries{
+		Items:   items,
+		Version: hex.EncodeToString(hash.Sum(nil)[:16]),
+	}
+
+	c.mu.Lock()
+	c.list = list
+	c.byId = byId
+	c.loadedAt = time.Now()
+	c.mu.Unlock()
+
+	return list, nil
+}
+
+func (s *Service) GetCountries(ctx context.Context) (_ Countries, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetCountries")
+	defer func() { tracing.End(span, err) }()
+
+	return s.countries.all(ctx)
+}
+
+func (s *Service) GetCountry(ctx context.Context, id int64) (_ *entity.Country, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetCountry")
+	defer func() { tracing.End(span, err) }()
+
+	country, err := s.countries.get(ctx, id)
+	if err != nil {
+		var cErr *cerrors.Error
+		if errors.As(err, &cErr) && cErr.Code == ercodes.CountryNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &country, nil
+}
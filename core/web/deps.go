@@ -21,7 +21,21 @@ type (
 		AddUsersAuthHistory(ctx context.Context, userId int64, agent, ip string) error
 		GetUserAuthHistory(ctx context.Context, userId int64) ([]UserAuthHistoryData, error)
 		GetUserWorkplaces(ctx context.Context, userId int64) ([]entity.UserWorkplace, error)
-		AddUserWorkplace(ctx context.Context, userId int64, work entity.Workplace) error
+		AddUserWorkplace(ctx context.Context, userId int64, work entity.Workplace) (int64, error)
+		UpdateUserWorkplace(ctx context.Context, userId, id int64, work entity.Workplace) error
+		DeleteUserWorkplace(ctx context.Context, userId, id int64) error
+		ScheduleUserDeletion(ctx context.Context, userId int64, deleteAt time.Time) error
+		CancelUserDeletion(ctx context.Context, userId int64) error
+		LockUserById(ctx context.Context, userId int64) error
+		ActivateUser(ctx context.Context, userId int64) error
+	}
+
+	CountryStorage interface {
+		GetCountries(ctx context.Context) ([]entity.Country, error)
+	}
+
+	Transactor interface {
+		WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 	}
 
 	RandomGenerator interface {
@@ -47,6 +61,7 @@ type (
 		SaveRefreshToken(ctx context.Context, token string, userId int64, ttl time.Duration) error
 		VerifyRefreshToken(ctx context.Context, token string) (int64, error)
 		ExpireAllByUserId(ctx context.Context, userId int64) error
+		GetSessionsByUserId(ctx context.Context, userId int64) ([]UserSession, error)
 	}
 
 	TwoFactorCodeStorage interface {
@@ -62,4 +77,19 @@ type (
 		SaveRecoveryCode(ctx context.Context, code string, userId int64, ttl time.Duration) error
 		VerifyRecoveryCode(ctx context.Context, code string) (int64, error)
 	}
+
+	AccessTokenRevoker interface {
+		RevokeAccessTokens(ctx context.Context, userId int64, issuedBefore time.Time, ttl time.Duration) error
+		AccessTokensRevokedAt(ctx context.Context, userId int64) (int64, error)
+	}
+
+	ExportLimiter interface {
+		AllowExport(ctx context.Context, userId int64, interval time.Duration) (bool, error)
+		ReleaseExport(ctx context.Context, userId int64) error
+	}
+
+	AuditLogger interface {
+		LogEvent(ctx context.Context, event AuditEvent) error
+		GetAuditEvents(ctx context.Context, filter AuditEventsFilter) ([]AuditEvent, error)
+	}
 )
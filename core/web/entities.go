@@ -3,14 +3,29 @@ package web
 import (
 	"time"
 	"x-bank-users/auth"
+	"x-bank-users/entity"
 )
 
 type (
+	AuthSettings struct {
+		HashCost          int
+		ClaimsTtl         time.Duration
+		RefreshTokenTtl   time.Duration
+		TwoFactorCodeTtl  time.Duration
+		RecoveryCodeTtl   time.Duration
+		ActivationCodeTtl time.Duration
+	}
+
+	RateLimits struct {
+		ExportInterval time.Duration
+	}
+
 	UserDataToSignIn struct {
 		Id              int64
 		PasswordHash    []byte
 		TelegramId      *int64
 		HasPersonalData bool
+		DeleteAt        *time.Time
 	}
 
 	SignInResult struct {
@@ -29,6 +44,7 @@ type (
 		Address         string
 		Gender          string
 		LiveInCountry   string
+		LiveInCountryId int64
 		UserEmployments []UserEmployment
 	}
 
@@ -59,4 +75,58 @@ type (
 		Ip        string
 		Timestamp time.Time
 	}
+
+	UserSession struct {
+		ExpiresAt time.Time
+	}
+
+	TwoFactorMethod struct {
+		Type       string
+		Identifier string
+	}
+
+	UserExport struct {
+		User             UserData
+		PersonalData     *UserPersonalData
+		Workplaces       []entity.UserWorkplace
+		AuthHistory      []UserAuthHistoryData
+		TwoFactorMethods []TwoFactorMethod
+		Sessions         []UserSession
+	}
+
+	AuditEvent struct {
+		Id        int64
+		ActorId   *int64
+		SubjectId int64
+		Action    string
+		Before    map[string]string
+		After     map[string]string
+		Details   map[string]string
+		Ip        string
+		Agent     string
+		RequestId string
+		CreatedAt time.Time
+	}
+
+	AuditEventsFilter struct {
+		ActorId   *int64
+		SubjectId *int64
+		Action    string
+		From      *time.Time
+		To        *time.Time
+		Limit     int
+		Offset    int
+	}
+
+	Countries struct {
+		Items   []entity.Country
+		Version string
+	}
+
+	RequestMeta struct {
+		ActorId   *int64
+		Ip        string
+		Agent     string
+		RequestId string
+	}
 )
@@ -0,0 +1,137 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+	"x-bank-users/cerrors"
+	"x-bank-users/entity"
+	"x-bank-users/ercodes"
+)
+
+type (
+	exportStorage struct {
+		UserStorage
+
+		calls int
+		err   error
+	}
+
+	exportSessions struct {
+		RefreshTokenStorage
+	}
+
+	memoryExportLimiter struct {
+		allowed  bool
+		interval time.Duration
+		released []int64
+	}
+)
+
+func (s *exportStorage) GetUserDataById(_ context.Context, id int64) (UserData, error) {
+	s.calls++
+	return UserData{Id: id, Login: "ivan"}, s.err
+}
+
+func (s *exportStorage) GetUserPersonalDataById(context.Context, int64) (*UserPersonalData, error) {
+	return nil, nil
+}
+
+func (s *exportStorage) GetUserWorkplaces(context.Context, int64) ([]entity.UserWorkplace, error) {
+	return nil, nil
+}
+
+func (s *exportStorage) GetUserAuthHistory(context.Context, int64) ([]UserAuthHistoryData, error) {
+	return nil, nil
+}
+
+func (exportSessions) GetSessionsByUserId(context.Context, int64) ([]UserSession, error) {
+	return nil, nil
+}
+
+func (l *memoryExportLimiter) AllowExport(_ context.Context, _ int64, interval time.Duration) (bool, error) {
+	l.interval = interval
+	return l.allowed, nil
+}
+
+func (l *memoryExportLimiter) ReleaseExport(_ context.Context, userId int64) error {
+	l.released = append(l.released, userId)
+	return nil
+}
+
+func newExportService(storage *exportStorage, limiter *memoryExportLimiter) (Service, *memoryAuditLogger) {
+	auditLogger := &memoryAuditLogger{}
+	service := Service{
+		userStorage:         storage,
+		refreshTokenStorage: exportSessions{},
+		exportLimiter:       limiter,
+		auditLogger:         auditLogger,
+		transactor:          passthroughTransactor{},
+		rateLimits:          &atomic.Pointer[RateLimits]{},
+	}
+	service.SetRateLimits(RateLimits{ExportInterval: time.Hour})
+
+	return service, auditLogger
+}
+
+func TestExportUserDataIsRateLimited(t *testing.T) {
+	storage := &exportStorage{}
+	limiter := &memoryExportLimiter{allowed: false}
+	service, auditLogger := newExportService(storage, limiter)
+
+	_, err := service.ExportUserData(context.Background(), 1, "json")
+
+	var cerr *cerrors.Error
+	if !errors.As(err, &cerr) || cerr.Code != ercodes.ExportRateLimited {
+		t.Fatalf("err = %v, want ExportRateLimited", err)
+	}
+	if limiter.interval != time.Hour {
+		t.Fatalf("interval = %s, want %s", limiter.interval, time.Hour)
+	}
+	if storage.calls != 0 {
+		t.Fatalf("storage was queried %d times while rate limited", storage.calls)
+	}
+	if len(limiter.released) != 0 {
+		t.Fatalf("slot released for a rejected export: %v", limiter.released)
+	}
+	if len(auditLogger.events) != 0 {
+		t.Fatalf("audit events = %d, want 0", len(auditLogger.events))
+	}
+}
+
+func TestExportUserDataReleasesSlotOnError(t *testing.T) {
+	storage := &exportStorage{err: errors.New("connection refused")}
+	limiter := &memoryExportLimiter{allowed: true}
+	service, _ := newExportService(storage, limiter)
+
+	if _, err := service.ExportUserData(context.Background(), 1, "zip"); err == nil {
+		t.Fatal("ExportUserData: expected error")
+	}
+	if len(limiter.released) != 1 || limiter.released[0] != 1 {
+		t.Fatalf("released = %v, want [1]", limiter.released)
+	}
+}
+
+func TestExportUserDataKeepsSlotOnSuccess(t *testing.T) {
+	limiter := &memoryExportLimiter{allowed: true}
+	service, auditLogger := newExportService(&exportStorage{}, limiter)
+
+	export, err := service.ExportUserData(context.Background(), 1, "zip")
+	if err != nil {
+		t.Fatalf("ExportUserData: %v", err)
+	}
+	if export.User.Login != "ivan" {
+		t.Fatalf("login = %q, want ivan", export.User.Login)
+	}
+	if len(limiter.released) != 0 {
+		t.Fatalf("slot released after a successful export: %v", limiter.released)
+	}
+	if len(auditLogger.events) != 1 || auditLogger.events[0].Action != AuditActionDataExported {
+		t.Fatalf("audit events = %+v, want one %s", auditLogger.events, AuditActionDataExported)
+	}
+	if got := auditLogger.events[0].Details["format"]; got != "zip" {
+		t.Fatalf("format = %q, want zip", got)
+	}
+}
@@ -0,0 +1,41 @@
+package web
+
+import (
+	"context"
+	"sync"
+	"x-bank-users/entity"
+)
+
+type (
+	passthroughTransactor struct{}
+
+	staticCountries []entity.Country
+
+	memoryAuditLogger struct {
+		mu     sync.Mutex
+		events []AuditEvent
+	}
+)
+
+func (passthroughTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (l *memoryAuditLogger) LogEvent(_ context.Context, event AuditEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *memoryAuditLogger) GetAuditEvents(context.Context, AuditEventsFilter) ([]AuditEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]AuditEvent(nil), l.events...), nil
+}
+
+func (c staticCountries) GetCountries(context.Context) ([]entity.Country, error) {
+	return c, nil
+}
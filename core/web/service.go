@@ -3,11 +3,17 @@ package web
 import (
 	"context"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
 	"time"
 	"x-bank-users/auth"
 	"x-bank-users/cerrors"
 	"x-bank-users/entity"
 	"x-bank-users/ercodes"
+	"x-bank-users/logging"
+	"x-bank-users/tracing"
 )
 
 type (
@@ -17,9 +23,19 @@ type (
 		activationCodeCache   ActivationCodeStorage
 		passwordHasher        PasswordHasher
 		refreshTokenStorage   RefreshTokenStorage
+		accessTokenRevoker    AccessTokenRevoker
 		twoFactorCodeStorage  TwoFactorCodeStorage
 		twoFactorCodeNotifier TwoFactorCodeNotifier
 		recoveryCodeStorage   RecoveryCodeStorage
+		exportLimiter         ExportLimiter
+		auditLogger           AuditLogger
+		countries             *countryCache
+
+		authSettings               AuthSettings
+		rateLimits                 *atomic.Pointer[RateLimits]
+		accountDeletionGracePeriod time.Duration
+		transactor                 Transactor
+		tracerProvider             trace.TracerProvider
 	}
 )
 
@@ -29,54 +45,116 @@ func NewService(
 	activationCodeCache ActivationCodeStorage,
 	passwordHasher PasswordHasher,
 	refreshTokenStorage RefreshTokenStorage,
+	accessTokenRevoker AccessTokenRevoker,
 	twoFactorCodeStorage TwoFactorCodeStorage,
 	twoFactorCodeNotifier TwoFactorCodeNotifier,
 	recoveryCodeStorage RecoveryCodeStorage,
+	exportLimiter ExportLimiter,
+	auditLogger AuditLogger,
+	authSettings AuthSettings,
+	rateLimits RateLimits,
+	accountDeletionGracePeriod time.Duration,
+	transactor Transactor,
+	countryStorage CountryStorage,
 ) Service {
-	return Service{
+	s := Service{
 		userStorage:           userStorage,
 		randomGenerator:       randomGenerator,
 		activationCodeCache:   activationCodeCache,
 		passwordHasher:        passwordHasher,
 		refreshTokenStorage:   refreshTokenStorage,
+		accessTokenRevoker:    accessTokenRevoker,
 		twoFactorCodeStorage:  twoFactorCodeStorage,
 		twoFactorCodeNotifier: twoFactorCodeNotifier,
 		recoveryCodeStorage:   recoveryCodeStorage,
+		exportLimiter:         exportLimiter,
+		auditLogger:           auditLogger,
+		countries:             newCountryCache(countryStorage),
+
+		authSettings:               authSettings,
+		rateLimits:                 &atomic.Pointer[RateLimits]{},
+		accountDeletionGracePeriod: accountDeletionGracePeriod,
+		transactor:                 transactor,
 	}
-}
+	s.SetRateLimits(rateLimits)
 
-const (
-	hashCost = 10
+	return s
+}
 
-	claimsTtl = time.Minute * 5
+func (s *Service) SetRateLimits(rateLimits RateLimits) {
+	s.rateLimits.Store(&rateLimits)
+}
 
+const (
 	refreshTokenCharset = ".-"
 	refreshTokenSize    = 2048
-	refreshTokenTtl     = time.Hour * 24 * 7
 
 	twoFactorCodeCharset = "0123456789"
 	twoFactorCodeSize    = 6
-	TwoFactorCodeTtl     = time.Minute * 5
 
 	recoveryCodeCharset = "ij"
 	recoveryCodeSize    = 16
-	recoveryCodeTtl     = time.Minute * 5
+
+	activationCodeCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+	activationCodeSize    = 32
+
+	twoFactorMethodTelegram = "telegram"
+
+	auditEventsDefaultLimit = 50
+	auditEventsMaxLimit     = 500
+)
+
+const (
+	AuditActionDataExported        = "user.data_exported"
+	AuditActionPasswordUpdated     = "user.password_updated"
+	AuditActionTelegramUpdated     = "user.telegram_updated"
+	AuditActionPersonalDataAdded   = "user.personal_data_added"
+	AuditActionPersonalDataUpdated = "user.personal_data_updated"
+	AuditActionWorkplaceAdded      = "user.workplace_added"
+	AuditActionWorkplaceUpdated    = "user.workplace_updated"
+	AuditActionWorkplaceDeleted    = "user.workplace_deleted"
+	AuditActionDeletionScheduled   = "user.deletion_scheduled"
+	AuditActionDeletionCancelled   = "user.deletion_cancelled"
 )
 
-func (s *Service) SignUp(ctx context.Context, login, password, email string) error {
-	hash, err := s.passwordHasher.HashPassword(ctx, []byte(password), hashCost)
+func (s *Service) SignUp(ctx context.Context, login, password, email string) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.SignUp")
+	defer func() { tracing.End(span, err) }()
+
+	hash, err := s.passwordHasher.HashPassword(ctx, []byte(password), s.authSettings.HashCost)
+	if err != nil {
+		return err
+	}
+
+	userId, err := s.userStorage.CreateUser(ctx, login, email, hash)
+	if err != nil {
+		return err
+	}
+
+	activationCode, err := s.randomGenerator.GenerateString(ctx, activationCodeCharset, activationCodeSize)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.userStorage.CreateUser(ctx, login, email, hash)
+	return s.activationCodeCache.SaveActivationCode(ctx, activationCode, userId, s.authSettings.ActivationCodeTtl)
+}
+
+func (s *Service) Activate(ctx context.Context, code string) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.Activate")
+	defer func() { tracing.End(span, err) }()
+
+	userId, err := s.activationCodeCache.VerifyActivationCode(ctx, code)
 	if err != nil {
 		return err
 	}
-	return err
+
+	return s.userStorage.ActivateUser(ctx, userId)
 }
 
-func (s *Service) SignIn(ctx context.Context, login, password, agent, ip string) (SignInResult, error) {
+func (s *Service) SignIn(ctx context.Context, login, password, agent, ip string) (_ SignInResult, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.SignIn")
+	defer func() { tracing.End(span, err) }()
+
 	userData, err := s.userStorage.GetSignInDataByLogin(ctx, login)
 	if err != nil {
 		return SignInResult{}, err
@@ -97,15 +175,20 @@ func (s *Service) SignIn(ctx context.Context, login, password, agent, ip string)
 		if err = s.userStorage.AddUsersAuthHistory(ctx, userData.Id, agent, ip); err != nil {
 			return SignInResult{}, err
 		}
+
+		if err = s.cancelScheduledDeletion(ctx, userData); err != nil {
+			return SignInResult{}, err
+		}
 	} else {
 		twoFactorCode, err := s.randomGenerator.GenerateString(ctx, twoFactorCodeCharset, twoFactorCodeSize)
 		if err != nil {
 			return SignInResult{}, err
 		}
-		if err = s.twoFactorCodeStorage.Save2FaCode(ctx, twoFactorCode, userData.Id, TwoFactorCodeTtl); err != nil {
+		if err = s.twoFactorCodeStorage.Save2FaCode(ctx, twoFactorCode, userData.Id, s.authSettings.TwoFactorCodeTtl); err != nil {
 			return SignInResult{}, err
 		}
 		if err = s.twoFactorCodeNotifier.Send2FaCode(ctx, *userData.TelegramId, twoFactorCode); err != nil {
+			logging.FromContext(ctx).Error("2fa code delivery failed", slog.Int64("userId", userData.Id), slog.String("error", err.Error()))
 			return SignInResult{}, err
 		}
 	}
@@ -114,7 +197,7 @@ func (s *Service) SignIn(ctx context.Context, login, password, agent, ip string)
 	claims := auth.Claims{
 		Id:              uuid.New().String(),
 		IssuedAt:        date.Unix(),
-		ExpiresAt:       date.Add(claimsTtl).Unix(),
+		ExpiresAt:       date.Add(s.authSettings.ClaimsTtl).Unix(),
 		Sub:             userData.Id,
 		Is2FAToken:      userData.TelegramId != nil,
 		HasPersonalData: userData.HasPersonalData,
@@ -126,14 +209,17 @@ func (s *Service) SignIn(ctx context.Context, login, password, agent, ip string)
 	}, nil
 }
 
-func (s *Service) SignIn2FA(ctx context.Context, claims auth.Claims, code, agent, ip string) (SignInResult, error) {
+func (s *Service) SignIn2FA(ctx context.Context, claims auth.Claims, code, agent, ip string) (_ SignInResult, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.SignIn2FA")
+	defer func() { tracing.End(span, err) }()
+
 	userId, err := s.twoFactorCodeStorage.Verify2FaCode(ctx, code)
 	if err != nil {
 		return SignInResult{}, err
 	}
 
 	if userId != claims.Sub {
-		return SignInResult{}, cerrors.NewErrorWithUserMessage(ercodes.Invalid2FACode, nil, "Неверный 2FA код")
+		return SignInResult{}, cerrors.NewErrorWithUserMessage(ercodes.Invalid2FACode, nil, "Неверный 2FA код").WithKind(cerrors.KindUnauthenticated)
 	}
 
 	personalData, err := s.userStorage.GetSignInDataById(ctx, userId)
@@ -145,6 +231,10 @@ func (s *Service) SignIn2FA(ctx context.Context, claims auth.Claims, code, agent
 		return SignInResult{}, err
 	}
 
+	if err = s.cancelScheduledDeletion(ctx, personalData); err != nil {
+		return SignInResult{}, err
+	}
+
 	hasPersonalData := personalData.HasPersonalData
 
 	refreshToken, err := s.randomGenerator.GenerateString(ctx, refreshTokenCharset, refreshTokenSize)
@@ -152,7 +242,7 @@ func (s *Service) SignIn2FA(ctx context.Context, claims auth.Claims, code, agent
 		return SignInResult{}, err
 	}
 
-	err = s.refreshTokenStorage.SaveRefreshToken(ctx, refreshToken, userId, refreshTokenTtl)
+	err = s.refreshTokenStorage.SaveRefreshToken(ctx, refreshToken, userId, s.authSettings.RefreshTokenTtl)
 	if err != nil {
 		return SignInResult{}, err
 	}
@@ -161,7 +251,7 @@ func (s *Service) SignIn2FA(ctx context.Context, claims auth.Claims, code, agent
 	accessClaims := auth.Claims{
 		Id:              uuid.New().String(),
 		IssuedAt:        timeNow.Unix(),
-		ExpiresAt:       timeNow.Add(claimsTtl).Unix(),
+		ExpiresAt:       timeNow.Add(s.authSettings.ClaimsTtl).Unix(),
 		Sub:             userId,
 		Is2FAToken:      false,
 		HasPersonalData: hasPersonalData,
@@ -173,7 +263,10 @@ func (s *Service) SignIn2FA(ctx context.Context, claims auth.Claims, code, agent
 	}, nil
 }
 
-func (s *Service) Recovery(ctx context.Context, login, email string) error {
+func (s *Service) Recovery(ctx context.Context, login, email string) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.Recovery")
+	defer func() { tracing.End(span, err) }()
+
 	userId, err := s.userStorage.UserIdByLoginAndEmail(ctx, login, email)
 	if err != nil {
 		return err
@@ -184,7 +277,7 @@ func (s *Service) Recovery(ctx context.Context, login, email string) error {
 		return err
 	}
 
-	err = s.recoveryCodeStorage.SaveRecoveryCode(ctx, recoveryCode, userId, recoveryCodeTtl)
+	err = s.recoveryCodeStorage.SaveRecoveryCode(ctx, recoveryCode, userId, s.authSettings.RecoveryCodeTtl)
 	if err != nil {
 		return err
 	}
@@ -192,26 +285,38 @@ func (s *Service) Recovery(ctx context.Context, login, email string) error {
 	return nil
 }
 
-func (s *Service) RecoveryCode(ctx context.Context, code, password string) error {
+func (s *Service) RecoveryCode(ctx context.Context, code, password string) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.RecoveryCode")
+	defer func() { tracing.End(span, err) }()
+
 	userId, err := s.recoveryCodeStorage.VerifyRecoveryCode(ctx, code)
 	if err != nil {
 		return err
 	}
 
-	hashedPassword, err := s.passwordHasher.HashPassword(ctx, []byte(password), hashCost)
+	hashedPassword, err := s.passwordHasher.HashPassword(ctx, []byte(password), s.authSettings.HashCost)
 	if err != nil {
 		return err
 	}
 
-	err = s.userStorage.UpdatePassword(ctx, userId, hashedPassword)
-	if err != nil {
-		return err
-	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.UpdatePassword(ctx, userId, hashedPassword); err != nil {
+			return err
+		}
 
-	return nil
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    AuditActionPasswordUpdated,
+			Before:    map[string]string{"password": maskedValue},
+			After:     map[string]string{"password": maskedValue},
+		})
+	})
 }
 
-func (s *Service) Refresh(ctx context.Context, token string) (SignInResult, error) {
+func (s *Service) Refresh(ctx context.Context, token string) (_ SignInResult, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.Refresh")
+	defer func() { tracing.End(span, err) }()
+
 	userId, err := s.refreshTokenStorage.VerifyRefreshToken(ctx, token)
 	if err != nil {
 		return SignInResult{}, err
@@ -231,7 +336,7 @@ func (s *Service) Refresh(ctx context.Context, token string) (SignInResult, erro
 	claims := auth.Claims{
 		Id:              uuid.New().String(),
 		IssuedAt:        date.Unix(),
-		ExpiresAt:       date.Add(claimsTtl).Unix(),
+		ExpiresAt:       date.Add(s.authSettings.ClaimsTtl).Unix(),
 		Sub:             userId,
 		Is2FAToken:      false,
 		HasPersonalData: userData.HasPersonalData,
@@ -246,53 +351,354 @@ func (s *Service) Refresh(ctx context.Context, token string) (SignInResult, erro
 func (s *Service) getNewToken(ctx context.Context, userId int64) (string, error) {
 	err := s.refreshTokenStorage.ExpireAllByUserId(ctx, userId)
 	if err != nil {
+		logging.FromContext(ctx).Error("previous sessions expiration failed", slog.Int64("userId", userId), slog.String("error", err.Error()))
 		return "", err
 	}
 	refreshToken, err := s.randomGenerator.GenerateString(ctx, refreshTokenCharset, refreshTokenSize)
 	if err != nil {
 		return "", err
 	}
-	if err = s.refreshTokenStorage.SaveRefreshToken(ctx, refreshToken, userId, refreshTokenTtl); err != nil {
+	if err = s.refreshTokenStorage.SaveRefreshToken(ctx, refreshToken, userId, s.authSettings.RefreshTokenTtl); err != nil {
 		return "", err
 	}
 	return refreshToken, nil
 }
 
-func (s *Service) BindTelegram(ctx context.Context, telegramId *int64, userId int64) error {
-	return s.userStorage.UpdateTelegramId(ctx, telegramId, userId)
+func (s *Service) BindTelegram(ctx context.Context, telegramId *int64, userId int64) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.BindTelegram")
+	defer func() { tracing.End(span, err) }()
+
+	return s.updateTelegramId(ctx, telegramId, userId)
 }
 
-func (s *Service) DeleteTelegram(ctx context.Context, userId int64) error {
-	return s.userStorage.UpdateTelegramId(ctx, nil, userId)
+func (s *Service) DeleteTelegram(ctx context.Context, userId int64) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.DeleteTelegram")
+	defer func() { tracing.End(span, err) }()
+
+	return s.updateTelegramId(ctx, nil, userId)
+}
+
+func (s *Service) updateTelegramId(ctx context.Context, telegramId *int64, userId int64) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.LockUserById(ctx, userId); err != nil {
+			return err
+		}
+
+		userData, err := s.userStorage.GetSignInDataById(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		if err = s.userStorage.UpdateTelegramId(ctx, telegramId, userId); err != nil {
+			return err
+		}
+
+		before, after := auditDiff(
+			map[string]string{"telegramId": int64OrEmpty(userData.TelegramId)},
+			map[string]string{"telegramId": int64OrEmpty(telegramId)},
+		)
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    AuditActionTelegramUpdated,
+			Before:    before,
+			After:     after,
+		})
+	})
 }
 
-func (s *Service) GetUserPersonalData(ctx context.Context, userId int64) (*UserPersonalData, error) {
+func (s *Service) GetUserPersonalData(ctx context.Context, userId int64) (_ *UserPersonalData, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetUserPersonalData")
+	defer func() { tracing.End(span, err) }()
+
 	return s.userStorage.GetUserPersonalDataById(ctx, userId)
 }
 
-func (s *Service) AddUserPersonalData(ctx context.Context, userId int64, data entity.UserPersonalData) error {
-	exist, err := s.GetUserPersonalData(ctx, userId)
-	if err != nil {
+func (s *Service) AddUserPersonalData(ctx context.Context, userId int64, data entity.UserPersonalData) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.AddUserPersonalData")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.countries.get(ctx, data.LiveInCountryId); err != nil {
 		return err
 	}
-	if exist != nil {
-		return s.userStorage.UpdateUserPersonalDataById(ctx, userId, data)
-	}
-	return s.userStorage.AddUserPersonalDataById(ctx, userId, data)
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.LockUserById(ctx, userId); err != nil {
+			return err
+		}
+
+		exist, err := s.userStorage.GetUserPersonalDataById(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		action := AuditActionPersonalDataAdded
+		if exist != nil {
+			action = AuditActionPersonalDataUpdated
+			err = s.userStorage.UpdateUserPersonalDataById(ctx, userId, data)
+		} else {
+			err = s.userStorage.AddUserPersonalDataById(ctx, userId, data)
+		}
+		if err != nil {
+			return err
+		}
+
+		before, after := auditDiff(storedPersonalDataValues(exist), personalDataValues(data))
+		if exist == nil {
+			before = nil
+		}
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    action,
+			Before:    before,
+			After:     after,
+		})
+	})
 }
 
-func (s *Service) GetUserData(ctx context.Context, userId int64) (UserData, error) {
+func (s *Service) GetUserData(ctx context.Context, userId int64) (_ UserData, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetUserData")
+	defer func() { tracing.End(span, err) }()
+
 	return s.userStorage.GetUserDataById(ctx, userId)
 }
 
-func (s *Service) GetAuthHistory(ctx context.Context, userId int64) ([]UserAuthHistoryData, error) {
+func (s *Service) GetAuthHistory(ctx context.Context, userId int64) (_ []UserAuthHistoryData, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetAuthHistory")
+	defer func() { tracing.End(span, err) }()
+
 	return s.userStorage.GetUserAuthHistory(ctx, userId)
 }
 
-func (s *Service) GetWorkplaces(ctx context.Context, userId int64) ([]entity.UserWorkplace, error) {
+func (s *Service) GetWorkplaces(ctx context.Context, userId int64) (_ []entity.UserWorkplace, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetWorkplaces")
+	defer func() { tracing.End(span, err) }()
+
 	return s.userStorage.GetUserWorkplaces(ctx, userId)
 }
 
-func (s *Service) AddWorkplace(ctx context.Context, userId int64, work entity.Workplace) error {
-	return s.userStorage.AddUserWorkplace(ctx, userId, work)
+func (s *Service) AddWorkplace(ctx context.Context, userId int64, work entity.Workplace) (_ int64, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.AddWorkplace")
+	defer func() { tracing.End(span, err) }()
+
+	var id int64
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		id, err = s.userStorage.AddUserWorkplace(ctx, userId, work)
+		if err != nil {
+			return err
+		}
+
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    AuditActionWorkplaceAdded,
+			After:     workplaceValues(id, work),
+		})
+	})
+
+	return id, err
+}
+
+func (s *Service) UpdateWorkplace(ctx context.Context, userId, id int64, work entity.Workplace) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.UpdateWorkplace")
+	defer func() { tracing.End(span, err) }()
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.LockUserById(ctx, userId); err != nil {
+			return err
+		}
+
+		workplaces, err := s.userStorage.GetUserWorkplaces(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		if err = s.userStorage.UpdateUserWorkplace(ctx, userId, id, work); err != nil {
+			return err
+		}
+
+		var old map[string]string
+		for _, workplace := range workplaces {
+			if workplace.Id == id {
+				old = storedWorkplaceValues(workplace)
+			}
+		}
+
+		before, after := auditDiff(old, workplaceValues(id, work))
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    AuditActionWorkplaceUpdated,
+			Before:    before,
+			After:     after,
+		})
+	})
+}
+
+func (s *Service) DeleteWorkplace(ctx context.Context, userId, id int64) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.DeleteWorkplace")
+	defer func() { tracing.End(span, err) }()
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.DeleteUserWorkplace(ctx, userId, id); err != nil {
+			return err
+		}
+
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    AuditActionWorkplaceDeleted,
+			Details:   map[string]string{"id": strconv.FormatInt(id, 10)},
+		})
+	})
+}
+
+func (s *Service) ExportUserData(ctx context.Context, userId int64, format string) (_ UserExport, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.ExportUserData")
+	defer func() { tracing.End(span, err) }()
+
+	allowed, err := s.exportLimiter.AllowExport(ctx, userId, s.rateLimits.Load().ExportInterval)
+	if err != nil {
+		return UserExport{}, err
+	}
+	if !allowed {
+		return UserExport{}, cerrors.NewErrorWithUserMessage(ercodes.ExportRateLimited, nil, "Выгрузка данных доступна не чаще одного раза в час")
+	}
+	defer func() {
+		if err != nil {
+			if releaseErr := s.exportLimiter.ReleaseExport(context.WithoutCancel(ctx), userId); releaseErr != nil {
+				logging.FromContext(ctx).Error("export slot release failed", slog.String("error", releaseErr.Error()))
+			}
+		}
+	}()
+
+	var export UserExport
+
+	if export.User, err = s.userStorage.GetUserDataById(ctx, userId); err != nil {
+		return UserExport{}, err
+	}
+	if export.PersonalData, err = s.userStorage.GetUserPersonalDataById(ctx, userId); err != nil {
+		return UserExport{}, err
+	}
+	if export.Workplaces, err = s.userStorage.GetUserWorkplaces(ctx, userId); err != nil {
+		return UserExport{}, err
+	}
+	if export.AuthHistory, err = s.userStorage.GetUserAuthHistory(ctx, userId); err != nil {
+		return UserExport{}, err
+	}
+	if export.Sessions, err = s.refreshTokenStorage.GetSessionsByUserId(ctx, userId); err != nil {
+		return UserExport{}, err
+	}
+
+	if export.User.TelegramId != nil {
+		export.TwoFactorMethods = append(export.TwoFactorMethods, TwoFactorMethod{
+			Type:       twoFactorMethodTelegram,
+			Identifier: strconv.FormatInt(*export.User.TelegramId, 10),
+		})
+	}
+
+	err = s.auditLogger.LogEvent(ctx, AuditEvent{
+		SubjectId: userId,
+		Action:    AuditActionDataExported,
+		Details:   map[string]string{"format": format},
+	})
+	if err != nil {
+		return UserExport{}, err
+	}
+
+	return export, nil
+}
+
+func (s *Service) GetAuditEvents(ctx context.Context, filter AuditEventsFilter) (_ []AuditEvent, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.GetAuditEvents")
+	defer func() { tracing.End(span, err) }()
+
+	if filter.Limit <= 0 {
+		filter.Limit = auditEventsDefaultLimit
+	}
+	if filter.Limit > auditEventsMaxLimit {
+		filter.Limit = auditEventsMaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.auditLogger.GetAuditEvents(ctx, filter)
+}
+
+func (s *Service) DeleteAccount(ctx context.Context, userId int64, password string) (_ time.Time, err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.DeleteAccount")
+	defer func() { tracing.End(span, err) }()
+
+	userData, err := s.userStorage.GetSignInDataById(ctx, userId)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err = s.passwordHasher.CompareHashAndPassword(ctx, password, userData.PasswordHash); err != nil {
+		return time.Time{}, err
+	}
+
+	deleteAt := time.Now().Add(s.accountDeletionGracePeriod)
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.ScheduleUserDeletion(ctx, userId, deleteAt); err != nil {
+			return err
+		}
+
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userId,
+			Action:    AuditActionDeletionScheduled,
+			After:     map[string]string{"deleteAt": formatAuditTime(deleteAt)},
+		})
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err = s.refreshTokenStorage.ExpireAllByUserId(ctx, userId); err != nil {
+		logging.FromContext(ctx).Error("sessions expiration after deletion request failed", slog.Int64("userId", userId), slog.String("error", err.Error()))
+		return time.Time{}, err
+	}
+
+	if err = s.accessTokenRevoker.RevokeAccessTokens(ctx, userId, time.Now(), s.authSettings.ClaimsTtl); err != nil {
+		logging.FromContext(ctx).Error("access tokens revocation after deletion request failed", slog.Int64("userId", userId), slog.String("error", err.Error()))
+		return time.Time{}, err
+	}
+
+	logging.FromContext(ctx).Info("account deletion scheduled", slog.Int64("userId", userId), slog.Time("deleteAt", deleteAt))
+	return deleteAt, nil
+}
+
+func (s *Service) VerifyAccessClaims(ctx context.Context, claims auth.Claims) (err error) {
+	ctx, span := s.tracer().Start(ctx, "web.Service.VerifyAccessClaims")
+	defer func() { tracing.End(span, err) }()
+
+	revokedAt, err := s.accessTokenRevoker.AccessTokensRevokedAt(ctx, claims.Sub)
+	if err != nil {
+		return err
+	}
+
+	if revokedAt != 0 && claims.IssuedAt <= revokedAt {
+		return cerrors.NewErrorWithUserMessage(ercodes.AccessTokenRevoked, nil, "Токен доступа отозван").WithKind(cerrors.KindUnauthenticated)
+	}
+
+	return nil
+}
+
+func (s *Service) cancelScheduledDeletion(ctx context.Context, userData UserDataToSignIn) error {
+	if userData.DeleteAt == nil {
+		return nil
+	}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.userStorage.CancelUserDeletion(ctx, userData.Id); err != nil {
+			return err
+		}
+
+		return s.auditLogger.LogEvent(ctx, AuditEvent{
+			SubjectId: userData.Id,
+			Action:    AuditActionDeletionCancelled,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Info("account deletion cancelled by sign in", slog.Int64("userId", userData.Id))
+	return nil
 }
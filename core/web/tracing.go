@@ -0,0 +1,15 @@
+package web
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "x-bank-users/core/web"
+
+func (s *Service) tracer() trace.Tracer {
+	if s.tracerProvider == nil {
+		return otel.Tracer(tracerName)
+	}
+	return s.tracerProvider.Tracer(tracerName)
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+type (
+	workplaceStorage struct {
+		UserStorage
+
+		err     error
+		spanCtx trace.SpanContext
+	}
+)
+
+func (s *workplaceStorage) DeleteUserWorkplace(ctx context.Context, _, _ int64) error {
+	s.spanCtx = trace.SpanContextFromContext(ctx)
+	return s.err
+}
+
+func deleteWorkplaceTraced(t *testing.T, storageErr error) (trace.SpanContext, *workplaceStorage, sdktrace.ReadOnlySpan, error) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	storage := &workplaceStorage{err: storageErr}
+	service := Service{userStorage: storage, transactor: passthroughTransactor{}, auditLogger: &memoryAuditLogger{}, tracerProvider: provider}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	err := service.DeleteWorkplace(ctx, 1, 7)
+	parent.End()
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == "web.Service.DeleteWorkplace" {
+			return parent.SpanContext(), storage, span, err
+		}
+	}
+	t.Fatal("web.Service.DeleteWorkplace span not recorded")
+	return trace.SpanContext{}, nil, nil, nil
+}
+
+func TestDeleteWorkplaceSpan(t *testing.T) {
+	parent, storage, span, err := deleteWorkplaceTraced(t, nil)
+	if err != nil {
+		t.Fatalf("DeleteWorkplace: %v", err)
+	}
+
+	if span.Parent().SpanID() != parent.SpanID() {
+		t.Fatalf("parent span id = %s, want %s", span.Parent().SpanID(), parent.SpanID())
+	}
+	if storage.spanCtx.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("storage span id = %s, want %s", storage.spanCtx.SpanID(), span.SpanContext().SpanID())
+	}
+	if span.Status().Code != codes.Unset {
+		t.Fatalf("span status = %s, want %s", span.Status().Code, codes.Unset)
+	}
+}
+
+func TestDeleteWorkplaceSpanRecordsError(t *testing.T) {
+	storageErr := errors.New("connection reset")
+	_, _, span, err := deleteWorkplaceTraced(t, storageErr)
+	if !errors.Is(err, storageErr) {
+		t.Fatalf("err = %v, want %v", err, storageErr)
+	}
+
+	if span.Status().Code != codes.Error || span.Status().Description != storageErr.Error() {
+		t.Fatalf("span status = %+v", span.Status())
+	}
+	if events := span.Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Fatalf("span events = %+v, want one exception", events)
+	}
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+	"x-bank-users/entity"
+)
+
+type (
+	SubscriptionStorage interface {
+		CreateWebhookSubscription(ctx context.Context, subscription Subscription) (Subscription, error)
+		GetWebhookSubscriptions(ctx context.Context) ([]Subscription, error)
+		GetActiveWebhookSubscriptions(ctx context.Context, eventType string) ([]Subscription, error)
+		DeleteWebhookSubscription(ctx context.Context, id int64) error
+	}
+
+	DeliveryStorage interface {
+		AddWebhookDelivery(ctx context.Context, delivery Delivery) error
+		GetWebhookDeliveries(ctx context.Context, subscriptionId int64, limit int) ([]Delivery, error)
+		AddWebhookDeadLetter(ctx context.Context, deadLetter DeadLetter) error
+		GetWebhookDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+	}
+
+	JobStorage interface {
+		EnqueueWebhookJobs(ctx context.Context, event entity.Event, subscriptionIds []int64) error
+		ClaimWebhookJobs(ctx context.Context, limit int, lease time.Duration) ([]Job, error)
+		CompleteWebhookJob(ctx context.Context, id int64) error
+		RetryWebhookJob(ctx context.Context, id int64, nextAttemptAt time.Time) error
+	}
+
+	Sender interface {
+		Send(ctx context.Context, url, secret string, event entity.Event) (int, error)
+	}
+
+	RandomGenerator interface {
+		GenerateString(ctx context.Context, set string, size int) (string, error)
+	}
+)
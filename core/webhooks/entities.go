@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	"time"
+	"x-bank-users/entity"
+)
+
+type (
+	Subscription struct {
+		Id        int64
+		Url       string
+		Secret    string
+		Events    []string
+		Active    bool
+		CreatedAt time.Time
+	}
+
+	Delivery struct {
+		Id             int64
+		SubscriptionId int64
+		EventId        string
+		EventType      string
+		Attempt        int
+		StatusCode     *int
+		Error          *string
+		Duration       time.Duration
+		CreatedAt      time.Time
+	}
+
+	Job struct {
+		Id           int64
+		Subscription Subscription
+		Attempts     int
+		Event        entity.Event
+	}
+
+	DeadLetter struct {
+		Id             int64
+		SubscriptionId int64
+		EventId        string
+		EventType      string
+		Payload        []byte
+		Attempts       int
+		LastError      string
+		CreatedAt      time.Time
+	}
+)
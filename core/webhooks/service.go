@@ -0,0 +1,233 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"x-bank-users/backoff"
+	"x-bank-users/cerrors"
+	"x-bank-users/entity"
+	"x-bank-users/ercodes"
+	"x-bank-users/logging"
+)
+
+type (
+	Service struct {
+		subscriptionStorage SubscriptionStorage
+		deliveryStorage     DeliveryStorage
+		jobStorage          JobStorage
+		sender              Sender
+		randomGenerator     RandomGenerator
+	}
+)
+
+func NewService(
+	subscriptionStorage SubscriptionStorage,
+	deliveryStorage DeliveryStorage,
+	jobStorage JobStorage,
+	sender Sender,
+	randomGenerator RandomGenerator,
+) Service {
+	return Service{
+		subscriptionStorage: subscriptionStorage,
+		deliveryStorage:     deliveryStorage,
+		jobStorage:          jobStorage,
+		sender:              sender,
+		randomGenerator:     randomGenerator,
+	}
+}
+
+const (
+	secretCharset = "0123456789abcdef"
+	secretSize    = 64
+
+	maxAttempts    = 5
+	retryBaseDelay = time.Second
+	retryMaxDelay  = time.Minute
+
+	pollInterval = time.Second
+	batchSize    = 10
+	claimLease   = time.Minute
+	sendTimeout  = 15 * time.Second
+
+	listLimit = 100
+)
+
+func (s *Service) CreateSubscription(ctx context.Context, url string, events []string) (Subscription, error) {
+	for _, eventType := range events {
+		if !isKnownEventType(eventType) {
+			return Subscription{}, cerrors.NewErrorWithUserMessage(ercodes.UnknownEventType, nil, "Неизвестный тип события "+eventType)
+		}
+	}
+
+	secret, err := s.randomGenerator.GenerateString(ctx, secretCharset, secretSize)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return s.subscriptionStorage.CreateWebhookSubscription(ctx, Subscription{
+		Url:    url,
+		Secret: secret,
+		Events: events,
+		Active: true,
+	})
+}
+
+func (s *Service) GetSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.subscriptionStorage.GetWebhookSubscriptions(ctx)
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id int64) error {
+	return s.subscriptionStorage.DeleteWebhookSubscription(ctx, id)
+}
+
+func (s *Service) GetDeliveries(ctx context.Context, subscriptionId int64) ([]Delivery, error) {
+	return s.deliveryStorage.GetWebhookDeliveries(ctx, subscriptionId, listLimit)
+}
+
+func (s *Service) GetDeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	return s.deliveryStorage.GetWebhookDeadLetters(ctx, listLimit)
+}
+
+func (s *Service) Publish(ctx context.Context, event entity.Event) error {
+	subscriptions, err := s.subscriptionStorage.GetActiveWebhookSubscriptions(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	subscriptionIds := make([]int64, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionIds[i] = subscription.Id
+	}
+
+	return s.jobStorage.EnqueueWebhookJobs(ctx, event, subscriptionIds)
+}
+
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			processed, err := s.ProcessBatch(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("webhook delivery failed", slog.String("error", err.Error()))
+				break
+			}
+			if processed < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) ProcessBatch(ctx context.Context) (int, error) {
+	jobs, err := s.jobStorage.ClaimWebhookJobs(ctx, batchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	errs := make([]error, len(jobs))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.deliver(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	return len(jobs), errors.Join(errs...)
+}
+
+func (s *Service) deliver(ctx context.Context, job Job) error {
+	subscription := job.Subscription
+	event := job.Event
+	attempt := job.Attempts + 1
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	start := time.Now()
+	statusCode, err := s.sender.Send(sendCtx, subscription.Url, subscription.Secret, event)
+	cancel()
+
+	delivery := Delivery{
+		SubscriptionId: subscription.Id,
+		EventId:        event.Id,
+		EventType:      event.Type,
+		Attempt:        attempt,
+		Duration:       time.Since(start),
+	}
+	if statusCode != 0 {
+		delivery.StatusCode = &statusCode
+	}
+	if err == nil && (statusCode < 200 || statusCode >= 300) {
+		err = fmt.Errorf("unexpected status code %d", statusCode)
+	}
+	if err != nil {
+		message := err.Error()
+		delivery.Error = &message
+	}
+
+	if logErr := s.deliveryStorage.AddWebhookDelivery(ctx, delivery); logErr != nil {
+		return logErr
+	}
+
+	if err == nil {
+		return s.jobStorage.CompleteWebhookJob(ctx, job.Id)
+	}
+
+	logger := logging.FromContext(ctx).With(
+		slog.Int64("subscriptionId", subscription.Id),
+		slog.String("eventId", event.Id),
+		slog.Int("attempt", attempt),
+	)
+
+	if attempt < maxAttempts {
+		logger.Warn("webhook delivery failed", slog.String("error", err.Error()))
+		return s.jobStorage.RetryWebhookJob(ctx, job.Id, time.Now().Add(backoff.Jittered(attempt-1, retryBaseDelay, retryMaxDelay)))
+	}
+
+	logger.Error("webhook delivery moved to dead letters", slog.String("error", err.Error()))
+
+	payload, marshalErr := json.Marshal(event)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	err = s.deliveryStorage.AddWebhookDeadLetter(ctx, DeadLetter{
+		SubscriptionId: subscription.Id,
+		EventId:        event.Id,
+		EventType:      event.Type,
+		Payload:        payload,
+		Attempts:       attempt,
+		LastError:      err.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.jobStorage.CompleteWebhookJob(ctx, job.Id)
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, known := range entity.EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
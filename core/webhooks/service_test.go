@@ -0,0 +1,255 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"x-bank-users/entity"
+	"x-bank-users/infra/webhook"
+)
+
+type (
+	memoryStorage struct {
+		mu            sync.Mutex
+		subscriptions []Subscription
+		jobs          map[int64]*memoryJob
+		deliveries    []Delivery
+		deadLetters   []DeadLetter
+	}
+
+	memoryJob struct {
+		job           Job
+		nextAttemptAt time.Time
+		completed     bool
+	}
+)
+
+func newMemoryStorage(subscriptions ...Subscription) *memoryStorage {
+	return &memoryStorage{subscriptions: subscriptions, jobs: make(map[int64]*memoryJob)}
+}
+
+func (m *memoryStorage) CreateWebhookSubscription(_ context.Context, subscription Subscription) (Subscription, error) {
+	return subscription, nil
+}
+
+func (m *memoryStorage) GetWebhookSubscriptions(context.Context) ([]Subscription, error) {
+	return m.subscriptions, nil
+}
+
+func (m *memoryStorage) GetActiveWebhookSubscriptions(context.Context, string) ([]Subscription, error) {
+	return m.subscriptions, nil
+}
+
+func (m *memoryStorage) DeleteWebhookSubscription(context.Context, int64) error {
+	return nil
+}
+
+func (m *memoryStorage) AddWebhookDelivery(_ context.Context, delivery Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *memoryStorage) GetWebhookDeliveries(context.Context, int64, int) ([]Delivery, error) {
+	return m.deliveries, nil
+}
+
+func (m *memoryStorage) AddWebhookDeadLetter(_ context.Context, deadLetter DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deadLetters = append(m.deadLetters, deadLetter)
+	return nil
+}
+
+func (m *memoryStorage) GetWebhookDeadLetters(context.Context, int) ([]DeadLetter, error) {
+	return m.deadLetters, nil
+}
+
+func (m *memoryStorage) EnqueueWebhookJobs(_ context.Context, event entity.Event, subscriptionIds []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, subscriptionId := range subscriptionIds {
+		for _, subscription := range m.subscriptions {
+			if subscription.Id != subscriptionId {
+				continue
+			}
+			id := int64(len(m.jobs) + 1)
+			m.jobs[id] = &memoryJob{job: Job{Id: id, Subscription: subscription, Event: event}}
+		}
+	}
+	return nil
+}
+
+func (m *memoryStorage) ClaimWebhookJobs(_ context.Context, limit int, lease time.Duration) ([]Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	var jobs []Job
+	for _, job := range m.jobs {
+		if job.completed || job.nextAttemptAt.After(now) || len(jobs) == limit {
+			continue
+		}
+		job.nextAttemptAt = now.Add(lease)
+		jobs = append(jobs, job.job)
+	}
+	return jobs, nil
+}
+
+func (m *memoryStorage) CompleteWebhookJob(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[id].completed = true
+	m.jobs[id].job.Attempts++
+	return nil
+}
+
+func (m *memoryStorage) RetryWebhookJob(_ context.Context, id int64, nextAttemptAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[id].nextAttemptAt = nextAttemptAt
+	m.jobs[id].job.Attempts++
+	return nil
+}
+
+func (m *memoryStorage) makeDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		job.nextAttemptAt = time.Time{}
+	}
+}
+
+func newTestService(t *testing.T, statusCode int) (*Service, *memoryStorage, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("X-Webhook-Signature") == "" {
+			t.Error("request is not signed")
+		}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	storage := newMemoryStorage(Subscription{Id: 1, Url: server.URL, Secret: "secret", Active: true})
+	sender := webhook.NewService("")
+	service := NewService(storage, storage, storage, &sender, nil)
+
+	return &service, storage, &requests
+}
+
+func testEvent() entity.Event {
+	return entity.Event{Id: "0b6f3c1e-8f2a-4c4e-9d43-7a2f3f7e9a10", Type: entity.EventUserSignedUp, Version: 1, Data: []byte(`{}`)}
+}
+
+func TestPublishOnlyEnqueues(t *testing.T) {
+	service, storage, requests := newTestService(t, http.StatusOK)
+
+	if err := service.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("Publish sent %d requests, want 0", got)
+	}
+	if got := len(storage.jobs); got != 1 {
+		t.Fatalf("enqueued %d jobs, want 1", got)
+	}
+}
+
+func TestProcessBatchDelivers(t *testing.T) {
+	service, storage, requests := newTestService(t, http.StatusNoContent)
+	ctx := context.Background()
+
+	if err := service.Publish(ctx, testEvent()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, err := service.ProcessBatch(ctx); err != nil {
+		t.Fatalf("ProcessBatch: %v", err)
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("sent %d requests, want 1", got)
+	}
+	if !storage.jobs[1].completed {
+		t.Fatal("job is not completed")
+	}
+	if len(storage.deliveries) != 1 || storage.deliveries[0].StatusCode == nil || *storage.deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("deliveries = %+v, want one with status 204", storage.deliveries)
+	}
+	if len(storage.deadLetters) != 0 {
+		t.Fatalf("dead letters = %+v, want none", storage.deadLetters)
+	}
+}
+
+func TestProcessBatchRetriesWithBackoff(t *testing.T) {
+	service, storage, _ := newTestService(t, http.StatusInternalServerError)
+	ctx := context.Background()
+
+	if err := service.Publish(ctx, testEvent()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		storage.makeDue()
+
+		before := time.Now()
+		if _, err := service.ProcessBatch(ctx); err != nil {
+			t.Fatalf("attempt %d: ProcessBatch: %v", attempt, err)
+		}
+
+		job := storage.jobs[1]
+		if job.completed {
+			t.Fatalf("attempt %d: job completed, want retry", attempt)
+		}
+		want := retryBaseDelay << (attempt - 1)
+		if delay := job.nextAttemptAt.Sub(before); delay < want/2 || delay > want+time.Second {
+			t.Fatalf("attempt %d: retry delay = %s, want within [%s, %s]", attempt, delay, want/2, want)
+		}
+	}
+
+	if got := len(storage.deliveries); got != maxAttempts-1 {
+		t.Fatalf("recorded %d deliveries, want %d", got, maxAttempts-1)
+	}
+	if len(storage.deadLetters) != 0 {
+		t.Fatalf("dead letters = %+v, want none before the last attempt", storage.deadLetters)
+	}
+}
+
+func TestProcessBatchDeadLettersAfterMaxAttempts(t *testing.T) {
+	service, storage, requests := newTestService(t, http.StatusServiceUnavailable)
+	ctx := context.Background()
+
+	if err := service.Publish(ctx, testEvent()); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	storage.jobs[1].job.Attempts = maxAttempts - 1
+
+	if _, err := service.ProcessBatch(ctx); err != nil {
+		t.Fatalf("ProcessBatch: %v", err)
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("sent %d requests, want 1", got)
+	}
+	if !storage.jobs[1].completed {
+		t.Fatal("dead-lettered job is still pending")
+	}
+	if len(storage.deadLetters) != 1 {
+		t.Fatalf("dead letters = %+v, want one", storage.deadLetters)
+	}
+	deadLetter := storage.deadLetters[0]
+	if deadLetter.Attempts != maxAttempts || deadLetter.SubscriptionId != 1 || deadLetter.EventId != testEvent().Id {
+		t.Fatalf("dead letter = %+v", deadLetter)
+	}
+	if deadLetter.LastError != "unexpected status code 503" {
+		t.Fatalf("dead letter error = %q", deadLetter.LastError)
+	}
+}
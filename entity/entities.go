@@ -14,6 +14,7 @@ type (
 	}
 
 	UserWorkplace struct {
+		Id             int64  `json:"id"`
 		CompanyName    string `json:"companyName"`
 		CompanyAddress string `json:"companyAddress"`
 		Position       string `json:"position"`
@@ -21,6 +22,15 @@ type (
 		EndDate        *int64 `json:"endDate"`
 	}
 
+	Country struct {
+		Id          int64  `json:"id"`
+		Alpha2      string `json:"alpha2"`
+		Alpha3      string `json:"alpha3"`
+		NameRu      string `json:"nameRu"`
+		NameEn      string `json:"nameEn"`
+		PhonePrefix string `json:"phonePrefix"`
+	}
+
 	Workplace struct {
 		CompanyName    string  `json:"companyName"`
 		CompanyAddress string  `json:"companyAddress"`
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventUserSignedUp           = "user.signed_up"
+	EventUserPersonalDataFilled = "user.personal_data_filled"
+	EventUserTelegramBound      = "user.telegram_bound"
+	EventUserTelegramUnbound    = "user.telegram_unbound"
+	EventUserDeleted            = "user.deleted"
+	EventUserPasswordChanged    = "user.password_changed"
+)
+
+var (
+	EventTypes = []string{
+		EventUserSignedUp,
+		EventUserPersonalDataFilled,
+		EventUserTelegramBound,
+		EventUserTelegramUnbound,
+		EventUserDeleted,
+		EventUserPasswordChanged,
+	}
+)
+
+type (
+	Event struct {
+		Id          string          `json:"id"`
+		Type        string          `json:"type"`
+		Version     int             `json:"version"`
+		AggregateId int64           `json:"aggregateId"`
+		OccurredAt  time.Time       `json:"occurredAt"`
+		Data        json.RawMessage `json:"data"`
+	}
+
+	EventPayload interface {
+		EventType() string
+		EventVersion() int
+	}
+
+	UserSignedUpEvent struct {
+		UserId int64  `json:"userId"`
+		Login  string `json:"login"`
+		Email  string `json:"email"`
+	}
+
+	UserPersonalDataFilledEvent struct {
+		UserId          int64 `json:"userId"`
+		LiveInCountryId int64 `json:"liveInCountry"`
+	}
+
+	UserTelegramBoundEvent struct {
+		UserId     int64 `json:"userId"`
+		TelegramId int64 `json:"telegramId"`
+	}
+
+	UserTelegramUnboundEvent struct {
+		UserId int64 `json:"userId"`
+	}
+
+	UserDeletedEvent struct {
+		UserId int64 `json:"userId"`
+	}
+
+	UserPasswordChangedEvent struct {
+		UserId int64 `json:"userId"`
+	}
+)
+
+func (UserSignedUpEvent) EventType() string { return EventUserSignedUp }
+func (UserSignedUpEvent) EventVersion() int { return 1 }
+
+func (UserPersonalDataFilledEvent) EventType() string { return EventUserPersonalDataFilled }
+func (UserPersonalDataFilledEvent) EventVersion() int { return 1 }
+
+func (UserTelegramBoundEvent) EventType() string { return EventUserTelegramBound }
+func (UserTelegramBoundEvent) EventVersion() int { return 1 }
+
+func (UserTelegramUnboundEvent) EventType() string { return EventUserTelegramUnbound }
+func (UserTelegramUnboundEvent) EventVersion() int { return 1 }
+
+func (UserDeletedEvent) EventType() string { return EventUserDeleted }
+func (UserDeletedEvent) EventVersion() int { return 1 }
+
+func (UserPasswordChangedEvent) EventType() string { return EventUserPasswordChanged }
+func (UserPasswordChangedEvent) EventVersion() int { return 1 }
@@ -24,4 +24,30 @@ const (
 	ExpireAllByUserIdError
 	InvalidLoginOrPassword
 	TelegramSendError
+	ExportRateLimited
+	Encryption
+	PassportAlreadyTaken
+	UnknownEventType
+	WebhookSubscriptionNotFound
+	WorkplaceNotFound
+	CountryNotFound
+	Validation
+	BadRequest
+	MethodNotAllowed
+	NotFound
+	Unauthorized
+	Fatal
+	Unknown
+	WorkplaceAddressMismatch
+	AccessTokenRevoked
+
+	end
 )
+
+func All() []cerrors.Code {
+	codes := make([]cerrors.Code, 0, -end)
+	for code := UserNotFound; code > end; code-- {
+		codes = append(codes, code)
+	}
+	return codes
+}
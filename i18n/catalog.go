@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"x-bank-users/cerrors"
+	"x-bank-users/vcodes"
+)
+
+type (
+	Message struct {
+		One   string
+		Few   string
+		Many  string
+		Other string
+	}
+
+	Bundle struct {
+		Errors     map[cerrors.Code]Message
+		Validation map[vcodes.Code]Message
+	}
+
+	Catalog struct {
+		bundles         map[string]Bundle
+		defaultLanguage string
+	}
+)
+
+const (
+	fallbackLanguage = "ru"
+)
+
+var (
+	bundles = map[string]Bundle{
+		"ru": ru,
+		"en": en,
+	}
+)
+
+func NewCatalog(defaultLanguage string) (Catalog, error) {
+	if defaultLanguage == "" {
+		defaultLanguage = fallbackLanguage
+	}
+	if _, ok := bundles[defaultLanguage]; !ok {
+		return Catalog{}, fmt.Errorf("неизвестный язык по умолчанию %q", defaultLanguage)
+	}
+
+	return Catalog{
+		bundles:         bundles,
+		defaultLanguage: defaultLanguage,
+	}, nil
+}
+
+func (c Catalog) Language(acceptLanguage string) string {
+	best, bestWeight := c.defaultLanguage, 0.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if _, ok := c.bundles[lang]; !ok {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		if weight > bestWeight {
+			best, bestWeight = lang, weight
+		}
+	}
+
+	return best
+}
+
+func (c Catalog) Error(lang string, code cerrors.Code) string {
+	return c.bundle(lang).Errors[code].format(lang, 1)
+}
+
+func (c Catalog) Validation(lang string, code vcodes.Code, n int) string {
+	return c.bundle(lang).Validation[code].format(lang, n)
+}
+
+func (c Catalog) bundle(lang string) Bundle {
+	if bundle, ok := c.bundles[lang]; ok {
+		return bundle
+	}
+	return c.bundles[c.defaultLanguage]
+}
+
+func (m Message) format(lang string, n int) string {
+	var text string
+	switch pluralForm(lang, n) {
+	case formOne:
+		text = m.One
+	case formFew:
+		text = m.Few
+	case formMany:
+		text = m.Many
+	}
+	if text == "" {
+		text = m.Other
+	}
+
+	if strings.Contains(text, "%d") {
+		return fmt.Sprintf(text, n)
+	}
+	return text
+}
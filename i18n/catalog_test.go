@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"x-bank-users/ercodes"
+	"x-bank-users/vcodes"
+)
+
+var countCodes = []vcodes.Code{vcodes.TooShort, vcodes.TooLong, vcodes.TooYoung}
+
+func TestEveryErrorCodeIsTranslated(t *testing.T) {
+	for lang, bundle := range bundles {
+		for _, code := range ercodes.All() {
+			if strings.TrimSpace(bundle.Errors[code].Other) == "" {
+				t.Errorf("%s: нет перевода ошибки %d", lang, code)
+			}
+		}
+	}
+}
+
+func TestEveryValidationCodeIsTranslated(t *testing.T) {
+	for lang, bundle := range bundles {
+		for _, code := range vcodes.All {
+			message := bundle.Validation[code]
+			if message.One == "" && message.Few == "" && message.Many == "" && message.Other == "" {
+				t.Errorf("%s: нет перевода ошибки валидации %s", lang, code)
+			}
+		}
+	}
+}
+
+func TestCountMessagesHaveEveryPluralForm(t *testing.T) {
+	for lang, bundle := range bundles {
+		for _, code := range countCodes {
+			message := bundle.Validation[code]
+			for _, n := range []int{0, 1, 2, 5, 11, 12, 21, 22, 25, 101} {
+				var text string
+				switch form := pluralForm(lang, n); form {
+				case formOne:
+					text = message.One
+				case formFew:
+					text = message.Few
+				case formMany:
+					text = message.Many
+				default:
+					text = message.Other
+				}
+				if text == "" {
+					t.Errorf("%s: %s: нет формы множественного числа для n=%d", lang, code, n)
+					continue
+				}
+
+				if got := message.format(lang, n); !strings.Contains(got, strconv.Itoa(n)) {
+					t.Errorf("%s: %s: %q не содержит %d", lang, code, got, n)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+	"x-bank-users/vcodes"
+)
+
+var en = Bundle{
+	Errors: map[cerrors.Code]Message{
+		ercodes.UserNotFound:                {Other: "User not found"},
+		ercodes.ActivationCodeNotFound:      {Other: "Activation code not found"},
+		ercodes.BcryptHashing:               {Other: "Password hashing failed"},
+		ercodes.RandomGeneration:            {Other: "Random number generation failed"},
+		ercodes.HS512Authorization:          {Other: "Invalid token"},
+		ercodes.RS256Authorization:          {Other: "Invalid token"},
+		ercodes.WrongPassword:               {Other: "Invalid login or password"},
+		ercodes.Invalid2FACode:              {Other: "Invalid 2FA code"},
+		ercodes.LoginAlreadyTaken:           {Other: "Login is already taken"},
+		ercodes.EmailAlreadyTaken:           {Other: "Email is already taken"},
+		ercodes.PostgresQuery:               {Other: "Database error"},
+		ercodes.PostgresScan:                {Other: "Database error"},
+		ercodes.RedisQuery:                  {Other: "Database error"},
+		ercodes.RecoveryCodeNotFound:        {Other: "Recovery code not found"},
+		ercodes.RefreshTokenNotFound:        {Other: "Refresh token not found"},
+		ercodes.TwoFaCodeNotFound:           {Other: "Two-factor authentication code not found"},
+		ercodes.ExpireAllByUserIdError:      {Other: "Failed to revoke sessions"},
+		ercodes.InvalidLoginOrPassword:      {Other: "Invalid login or password"},
+		ercodes.TelegramSendError:           {Other: "Failed to send the code"},
+		ercodes.ExportRateLimited:           {Other: "Data export is available once per hour"},
+		ercodes.Encryption:                  {Other: "Data encryption error"},
+		ercodes.PassportAlreadyTaken:        {Other: "Passport is already in use"},
+		ercodes.UnknownEventType:            {Other: "Unknown event type"},
+		ercodes.WebhookSubscriptionNotFound: {Other: "Subscription not found"},
+		ercodes.WorkplaceNotFound:           {Other: "Workplace not found"},
+		ercodes.CountryNotFound:             {Other: "Unknown country of residence"},
+		ercodes.Validation:                  {Other: "Validation failed"},
+		ercodes.BadRequest:                  {Other: "Bad request"},
+		ercodes.MethodNotAllowed:            {Other: "Method not allowed"},
+		ercodes.NotFound:                    {Other: "Not found"},
+		ercodes.Unauthorized:                {Other: "Unauthorized"},
+		ercodes.Fatal:                       {Other: "Fatal error"},
+		ercodes.Unknown:                     {Other: "Unknown error"},
+		ercodes.WorkplaceAddressMismatch:    {Other: "Company is already registered with a different address"},
+		ercodes.AccessTokenRevoked:          {Other: "Session has been terminated, sign in again"},
+	},
+	Validation: map[vcodes.Code]Message{
+		vcodes.Required:      {Other: "Required field"},
+		vcodes.InvalidValue:  {Other: "Invalid value"},
+		vcodes.InvalidFormat: {Other: "Invalid format"},
+		vcodes.TooShort:      {One: "At least %d character", Other: "At least %d characters"},
+		vcodes.TooLong:       {One: "At most %d character", Other: "At most %d characters"},
+		vcodes.InFuture:      {Other: "Date cannot be in the future"},
+		vcodes.TooYoung:      {One: "Minimum age is %d year", Other: "Minimum age is %d years"},
+		vcodes.InvalidRange:  {Other: "End date must be after start date"},
+		vcodes.Unknown:       {Other: "Value not found in the reference list"},
+	},
+}
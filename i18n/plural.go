@@ -0,0 +1,34 @@
+package i18n
+
+type form int
+
+const (
+	formOther form = iota
+	formOne
+	formFew
+	formMany
+)
+
+func pluralForm(lang string, n int) form {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case "ru":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return formOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return formFew
+		default:
+			return formMany
+		}
+	case "en":
+		if n == 1 {
+			return formOne
+		}
+	}
+
+	return formOther
+}
@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+	"x-bank-users/vcodes"
+)
+
+var ru = Bundle{
+	Errors: map[cerrors.Code]Message{
+		ercodes.UserNotFound:                {Other: "Пользователь не найден"},
+		ercodes.ActivationCodeNotFound:      {Other: "Код активации не найден"},
+		ercodes.BcryptHashing:               {Other: "Ошибка хэширования пароля"},
+		ercodes.RandomGeneration:            {Other: "Ошибка генерации случайного числа"},
+		ercodes.HS512Authorization:          {Other: "Токен не валиден"},
+		ercodes.RS256Authorization:          {Other: "Токен не валиден"},
+		ercodes.WrongPassword:               {Other: "Неверный логин или пароль"},
+		ercodes.Invalid2FACode:              {Other: "Неверный 2FA код"},
+		ercodes.LoginAlreadyTaken:           {Other: "Логин уже занят"},
+		ercodes.EmailAlreadyTaken:           {Other: "Емейл уже занят"},
+		ercodes.PostgresQuery:               {Other: "Ошибка работы с базой данных"},
+		ercodes.PostgresScan:                {Other: "Ошибка работы с базой данных"},
+		ercodes.RedisQuery:                  {Other: "Ошибка работы с базой данных"},
+		ercodes.RecoveryCodeNotFound:        {Other: "Код восстановления не найден"},
+		ercodes.RefreshTokenNotFound:        {Other: "Токен не найден"},
+		ercodes.TwoFaCodeNotFound:           {Other: "Код двухфакторной аутентификации не найден"},
+		ercodes.ExpireAllByUserIdError:      {Other: "Ошибка удаления токенов"},
+		ercodes.InvalidLoginOrPassword:      {Other: "Неверный логин или пароль"},
+		ercodes.TelegramSendError:           {Other: "Ошибка отправки кода"},
+		ercodes.ExportRateLimited:           {Other: "Выгрузка данных доступна не чаще одного раза в час"},
+		ercodes.Encryption:                  {Other: "Ошибка шифрования данных"},
+		ercodes.PassportAlreadyTaken:        {Other: "Паспорт уже используется"},
+		ercodes.UnknownEventType:            {Other: "Неизвестный тип события"},
+		ercodes.WebhookSubscriptionNotFound: {Other: "Подписка не найдена"},
+		ercodes.WorkplaceNotFound:           {Other: "Место работы не найдено"},
+		ercodes.CountryNotFound:             {Other: "Неизвестная страна проживания"},
+		ercodes.Validation:                  {Other: "Ошибка валидации"},
+		ercodes.BadRequest:                  {Other: "Ошибка запроса"},
+		ercodes.MethodNotAllowed:            {Other: "Метод не поддерживается"},
+		ercodes.NotFound:                    {Other: "Не найдено"},
+		ercodes.Unauthorized:                {Other: "Не авторизован"},
+		ercodes.Fatal:                       {Other: "Фатальная ошибка"},
+		ercodes.Unknown:                     {Other: "Неизвестная ошибка"},
+		ercodes.WorkplaceAddressMismatch:    {Other: "Компания уже зарегистрирована с другим адресом"},
+		ercodes.AccessTokenRevoked:          {Other: "Сессия завершена, войдите снова"},
+	},
+	Validation: map[vcodes.Code]Message{
+		vcodes.Required:      {Other: "Обязательное поле"},
+		vcodes.InvalidValue:  {Other: "Недопустимое значение"},
+		vcodes.InvalidFormat: {Other: "Неверный формат"},
+		vcodes.TooShort:      {One: "Минимум %d символ", Few: "Минимум %d символа", Many: "Минимум %d символов"},
+		vcodes.TooLong:       {One: "Максимум %d символ", Few: "Максимум %d символа", Many: "Максимум %d символов"},
+		vcodes.InFuture:      {Other: "Дата не может быть в будущем"},
+		vcodes.TooYoung:      {One: "Минимальный возраст - %d год", Few: "Минимальный возраст - %d года", Many: "Минимальный возраст - %d лет"},
+		vcodes.InvalidRange:  {Other: "Дата окончания должна быть позже даты начала"},
+		vcodes.Unknown:       {Other: "Значение не найдено в справочнике"},
+	},
+}
@@ -0,0 +1,148 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"x-bank-users/core/cleaner"
+)
+
+const (
+	authHistoryFilePrefix = "users_auth_history-"
+	fileExtension         = ".ndjson.gz"
+)
+
+type (
+	Service struct {
+		dir string
+
+		archivedIds map[string]map[int64]struct{}
+	}
+)
+
+func NewService(dir string) Service {
+	return Service{
+		dir:         dir,
+		archivedIds: make(map[string]map[int64]struct{}),
+	}
+}
+
+func (s *Service) ArchiveAuthHistory(_ context.Context, records []cleaner.AuthHistoryRecord) error {
+	byMonth := make(map[string][]cleaner.AuthHistoryRecord)
+	for _, record := range records {
+		month := record.Timestamp.Format("2006-01")
+		byMonth[month] = append(byMonth[month], record)
+	}
+
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+
+	for _, month := range months {
+		if err := s.appendRecords(filepath.Join(s.dir, authHistoryFilePrefix+month+fileExtension), byMonth[month]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) appendRecords(path string, records []cleaner.AuthHistoryRecord) (err error) {
+	archived, ok := s.archivedIds[path]
+	if !ok {
+		if archived, err = readArchivedIds(path); err != nil {
+			return err
+		}
+		s.archivedIds[path] = archived
+	}
+
+	pending := records[:0:0]
+	for _, record := range records {
+		if _, ok = archived[record.Id]; !ok {
+			pending = append(pending, record)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, file.Close()) }()
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	if err = writeGzipMember(file, pending); err != nil {
+		return errors.Join(err, file.Truncate(offset))
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+
+	for _, record := range pending {
+		archived[record.Id] = struct{}{}
+	}
+
+	return nil
+}
+
+func readArchivedIds(path string) (_ map[int64]struct{}, err error) {
+	ids := make(map[int64]struct{})
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, file.Close()) }()
+
+	gz, err := gzip.NewReader(file)
+	if errors.Is(err, io.EOF) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(gz)
+	for {
+		var record cleaner.AuthHistoryRecord
+		if err = decoder.Decode(&record); errors.Is(err, io.EOF) {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids[record.Id] = struct{}{}
+	}
+}
+
+func writeGzipMember(w io.Writer, records []cleaner.AuthHistoryRecord) error {
+	gz := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return gz.Close()
+}
@@ -0,0 +1,175 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	"x-bank-users/core/cleaner"
+)
+
+func record(id int64, timestamp string) cleaner.AuthHistoryRecord {
+	ts, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		panic(err)
+	}
+	return cleaner.AuthHistoryRecord{Id: id, UserId: 100 + id, Agent: "curl/8.0", Ip: "10.0.0.1", Timestamp: ts}
+}
+
+func readArchive(t *testing.T, path string) []cleaner.AuthHistoryRecord {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip %s: %v", path, err)
+	}
+
+	var records []cleaner.AuthHistoryRecord
+	decoder := json.NewDecoder(gz)
+	for {
+		var r cleaner.AuthHistoryRecord
+		if err = decoder.Decode(&r); errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("decode %s: %v", path, err)
+		}
+		records = append(records, r)
+	}
+}
+
+func archiveIds(t *testing.T, path string) []int64 {
+	t.Helper()
+
+	var ids []int64
+	for _, r := range readArchive(t, path) {
+		ids = append(ids, r.Id)
+	}
+	return ids
+}
+
+func TestArchiveSplitsRecordsByMonth(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	service := NewService(dir)
+
+	records := []cleaner.AuthHistoryRecord{
+		record(1, "2024-01-31T23:59:59Z"),
+		record(2, "2024-02-01T00:00:00Z"),
+		record(3, "2024-01-15T12:00:00Z"),
+	}
+	if err := service.ArchiveAuthHistory(context.Background(), records); err != nil {
+		t.Fatalf("ArchiveAuthHistory: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	want := []string{"users_auth_history-2024-01.ndjson.gz", "users_auth_history-2024-02.ndjson.gz"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("files = %v, want %v", names, want)
+	}
+
+	january := readArchive(t, filepath.Join(dir, want[0]))
+	if !reflect.DeepEqual(january, []cleaner.AuthHistoryRecord{records[0], records[2]}) {
+		t.Fatalf("january = %+v", january)
+	}
+}
+
+func TestArchiveAppendsGzipMembers(t *testing.T) {
+	dir := t.TempDir()
+	service := NewService(dir)
+	ctx := context.Background()
+	path := filepath.Join(dir, "users_auth_history-2024-01.ndjson.gz")
+
+	if err := service.ArchiveAuthHistory(ctx, []cleaner.AuthHistoryRecord{record(1, "2024-01-01T00:00:00Z"), record(2, "2024-01-02T00:00:00Z")}); err != nil {
+		t.Fatalf("first ArchiveAuthHistory: %v", err)
+	}
+	if err := service.ArchiveAuthHistory(ctx, []cleaner.AuthHistoryRecord{record(3, "2024-01-03T00:00:00Z")}); err != nil {
+		t.Fatalf("second ArchiveAuthHistory: %v", err)
+	}
+
+	if got, want := archiveIds(t, path), []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("archived ids = %v, want %v", got, want)
+	}
+}
+
+func TestArchiveSkipsAlreadyArchivedRecords(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	path := filepath.Join(dir, "users_auth_history-2024-01.ndjson.gz")
+	batch := []cleaner.AuthHistoryRecord{record(1, "2024-01-01T00:00:00Z"), record(2, "2024-01-02T00:00:00Z")}
+
+	service := NewService(dir)
+	if err := service.ArchiveAuthHistory(ctx, batch); err != nil {
+		t.Fatalf("ArchiveAuthHistory: %v", err)
+	}
+	if err := service.ArchiveAuthHistory(ctx, batch); err != nil {
+		t.Fatalf("repeated ArchiveAuthHistory: %v", err)
+	}
+	if got, want := archiveIds(t, path), []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("archived ids after retry = %v, want %v", got, want)
+	}
+
+	restarted := NewService(dir)
+	overlapping := append(batch, record(3, "2024-01-03T00:00:00Z"))
+	if err := restarted.ArchiveAuthHistory(ctx, overlapping); err != nil {
+		t.Fatalf("ArchiveAuthHistory after restart: %v", err)
+	}
+	if got, want := archiveIds(t, path), []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("archived ids after restart = %v, want %v", got, want)
+	}
+}
+
+func TestArchiveTreatsEmptyFileAsEmptyArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users_auth_history-2024-01.ndjson.gz")
+	if err := os.WriteFile(path, nil, 0o640); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	service := NewService(dir)
+	if err := service.ArchiveAuthHistory(context.Background(), []cleaner.AuthHistoryRecord{record(1, "2024-01-01T00:00:00Z")}); err != nil {
+		t.Fatalf("ArchiveAuthHistory: %v", err)
+	}
+	if got, want := archiveIds(t, path), []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("archived ids = %v, want %v", got, want)
+	}
+}
+
+func TestArchiveRejectsCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users_auth_history-2024-01.ndjson.gz")
+	if err := os.WriteFile(path, []byte("not gzip"), 0o640); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	service := NewService(dir)
+	if err := service.ArchiveAuthHistory(context.Background(), []cleaner.AuthHistoryRecord{record(1, "2024-01-01T00:00:00Z")}); err == nil {
+		t.Fatal("ArchiveAuthHistory: expected error for a corrupt archive")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(content) != "not gzip" {
+		t.Fatalf("corrupt archive was modified: %q", content)
+	}
+}
@@ -0,0 +1,108 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+)
+
+type (
+	LocalKMS struct {
+		currentKeyId string
+		keys         map[string]cipher.AEAD
+	}
+
+	localKMSFile struct {
+		CurrentKeyId string            `json:"currentKeyId"`
+		Keys         map[string]string `json:"keys"`
+	}
+)
+
+func NewLocalKMS(currentKeyId string, masterKeys map[string]string) (LocalKMS, error) {
+	keys := make(map[string]cipher.AEAD, len(masterKeys))
+	for keyId, masterKey := range masterKeys {
+		key, err := hex.DecodeString(masterKey)
+		if err != nil {
+			return LocalKMS{}, err
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return LocalKMS{}, err
+		}
+		keys[keyId] = aead
+	}
+
+	if _, ok := keys[currentKeyId]; !ok {
+		return LocalKMS{}, errors.New("текущий мастер-ключ не найден")
+	}
+
+	return LocalKMS{
+		currentKeyId: currentKeyId,
+		keys:         keys,
+	}, nil
+}
+
+func NewLocalKMSFromFile(path string) (LocalKMS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LocalKMS{}, err
+	}
+
+	var f localKMSFile
+	if err = json.Unmarshal(data, &f); err != nil {
+		return LocalKMS{}, err
+	}
+
+	return NewLocalKMS(f.CurrentKeyId, f.Keys)
+}
+
+func (k *LocalKMS) CurrentKeyId() string {
+	return k.currentKeyId
+}
+
+func (k *LocalKMS) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	aead := k.keys[k.currentKeyId]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка шифрования данных").WithKind(cerrors.KindInternal)
+	}
+
+	return k.currentKeyId, aead.Seal(nonce, nonce, dataKey, []byte(k.currentKeyId)), nil
+}
+
+func (k *LocalKMS) UnwrapKey(_ context.Context, keyId string, wrappedKey []byte) ([]byte, error) {
+	aead, ok := k.keys[keyId]
+	if !ok {
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, errors.New("неизвестный мастер-ключ "+keyId), "Ошибка расшифровки данных").WithKind(cerrors.KindInternal)
+	}
+
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, errors.New("неверный размер ключа"), "Ошибка расшифровки данных").WithKind(cerrors.KindInternal)
+	}
+
+	nonce, ciphertext := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, ciphertext, []byte(keyId))
+	if err != nil {
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка расшифровки данных").WithKind(cerrors.KindInternal)
+	}
+
+	return dataKey, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
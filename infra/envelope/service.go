@@ -0,0 +1,107 @@
+package envelope
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+)
+
+type (
+	KeyManager interface {
+		CurrentKeyId() string
+		WrapKey(ctx context.Context, dataKey []byte) (string, []byte, error)
+		UnwrapKey(ctx context.Context, keyId string, wrappedKey []byte) ([]byte, error)
+	}
+
+	Service struct {
+		keyManager    KeyManager
+		blindIndexKey []byte
+	}
+)
+
+const (
+	dataKeySize = 32
+)
+
+func NewService(keyManager KeyManager, blindIndexKey string) (Service, error) {
+	key, err := hex.DecodeString(blindIndexKey)
+	if err != nil {
+		return Service{}, err
+	}
+
+	if len(key) == 0 {
+		return Service{}, errors.New("не задан ключ слепого индекса")
+	}
+
+	return Service{
+		keyManager:    keyManager,
+		blindIndexKey: key,
+	}, nil
+}
+
+func (s *Service) CurrentKeyId() string {
+	return s.keyManager.CurrentKeyId()
+}
+
+func (s *Service) NewDataKey(ctx context.Context) ([]byte, string, []byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, "", nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка шифрования данных").WithKind(cerrors.KindInternal)
+	}
+
+	keyId, wrappedKey, err := s.keyManager.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return dataKey, keyId, wrappedKey, nil
+}
+
+func (s *Service) UnwrapDataKey(ctx context.Context, keyId string, wrappedKey []byte) ([]byte, error) {
+	return s.keyManager.UnwrapKey(ctx, keyId, wrappedKey)
+}
+
+func (s *Service) Encrypt(dataKey []byte, plaintext string, aad []byte) ([]byte, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка шифрования данных").WithKind(cerrors.KindInternal)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка шифрования данных").WithKind(cerrors.KindInternal)
+	}
+
+	return aead.Seal(nonce, nonce, []byte(plaintext), aad), nil
+}
+
+func (s *Service) Decrypt(dataKey []byte, ciphertext []byte, aad []byte) (string, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка расшифровки данных").WithKind(cerrors.KindInternal)
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return "", cerrors.NewErrorWithUserMessage(ercodes.Encryption, errors.New("неверный размер шифротекста"), "Ошибка расшифровки данных").WithKind(cerrors.KindInternal)
+	}
+
+	nonce, data := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, data, aad)
+	if err != nil {
+		return "", cerrors.NewErrorWithUserMessage(ercodes.Encryption, err, "Ошибка расшифровки данных").WithKind(cerrors.KindInternal)
+	}
+
+	return string(plaintext), nil
+}
+
+func (s *Service) BlindIndex(value string) []byte {
+	mac := hmac.New(sha256.New, s.blindIndexKey)
+	mac.Write([]byte(strings.ToUpper(strings.Join(strings.Fields(value), ""))))
+	return mac.Sum(nil)
+}
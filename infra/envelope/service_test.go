@@ -0,0 +1,131 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func newTestService(t *testing.T) (Service, *LocalKMS) {
+	t.Helper()
+
+	kms, err := NewLocalKMS("k2", map[string]string{
+		"k1": strings.Repeat("11", 32),
+		"k2": strings.Repeat("22", 32),
+	})
+	if err != nil {
+		t.Fatalf("NewLocalKMS: %v", err)
+	}
+
+	service, err := NewService(&kms, strings.Repeat("33", 32))
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	return service, &kms
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	service, _ := newTestService(t)
+	ctx := context.Background()
+
+	dataKey, keyId, wrappedKey, err := service.NewDataKey(ctx)
+	if err != nil {
+		t.Fatalf("NewDataKey: %v", err)
+	}
+	if keyId != "k2" {
+		t.Fatalf("keyId = %q, want k2", keyId)
+	}
+
+	aad := []byte("users_personal_data/1/passportId")
+	ciphertext, err := service.Encrypt(dataKey, "4510 123456", aad)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("4510 123456")) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+
+	unwrapped, err := service.UnwrapDataKey(ctx, keyId, wrappedKey)
+	if err != nil {
+		t.Fatalf("UnwrapDataKey: %v", err)
+	}
+	plaintext, err := service.Decrypt(unwrapped, ciphertext, aad)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if plaintext != "4510 123456" {
+		t.Fatalf("plaintext = %q, want %q", plaintext, "4510 123456")
+	}
+}
+
+func TestEnvelopeRejectsTampering(t *testing.T) {
+	service, kms := newTestService(t)
+	ctx := context.Background()
+
+	dataKey, keyId, wrappedKey, err := service.NewDataKey(ctx)
+	if err != nil {
+		t.Fatalf("NewDataKey: %v", err)
+	}
+	aad := []byte("users_personal_data/1/passportId")
+	ciphertext, err := service.Encrypt(dataKey, "4510 123456", aad)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	flipped := append([]byte(nil), ciphertext...)
+	flipped[len(flipped)-1] ^= 0x01
+	if _, err = service.Decrypt(dataKey, flipped, aad); err == nil {
+		t.Error("Decrypt accepted a modified ciphertext")
+	}
+
+	for _, otherAAD := range [][]byte{
+		[]byte("users_personal_data/2/passportId"),
+		[]byte("users_personal_data/1/address"),
+		nil,
+	} {
+		if _, err = service.Decrypt(dataKey, ciphertext, otherAAD); err == nil {
+			t.Errorf("Decrypt accepted the ciphertext with aad %q", otherAAD)
+		}
+	}
+
+	if _, err = service.Decrypt(dataKey, ciphertext[:4], aad); err == nil {
+		t.Error("Decrypt accepted a truncated ciphertext")
+	}
+
+	otherKey, _, _, err := service.NewDataKey(ctx)
+	if err != nil {
+		t.Fatalf("NewDataKey: %v", err)
+	}
+	if _, err = service.Decrypt(otherKey, ciphertext, aad); err == nil {
+		t.Error("Decrypt accepted a foreign data key")
+	}
+
+	if _, err = kms.UnwrapKey(ctx, "k1", wrappedKey); err == nil {
+		t.Error("UnwrapKey accepted a data key wrapped under another master key")
+	}
+	if _, err = kms.UnwrapKey(ctx, "k3", wrappedKey); err == nil {
+		t.Error("UnwrapKey accepted an unknown master key")
+	}
+
+	wrappedFlipped := append([]byte(nil), wrappedKey...)
+	wrappedFlipped[len(wrappedFlipped)-1] ^= 0x01
+	if _, err = kms.UnwrapKey(ctx, keyId, wrappedFlipped); err == nil {
+		t.Error("UnwrapKey accepted a modified wrapped key")
+	}
+}
+
+func TestBlindIndexNormalizesPassport(t *testing.T) {
+	service, _ := newTestService(t)
+
+	want := service.BlindIndex("4510 123456")
+	for _, value := range []string{"4510123456", " 4510  123456 ", "4510\t123456"} {
+		if got := service.BlindIndex(value); !bytes.Equal(got, want) {
+			t.Errorf("BlindIndex(%q) differs from BlindIndex(%q)", value, "4510 123456")
+		}
+	}
+	if bytes.Equal(service.BlindIndex("4510 123457"), want) {
+		t.Error("different passports share a blind index")
+	}
+}
@@ -2,11 +2,15 @@ package hasher
 
 import (
 	"context"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/bcrypt"
 	"x-bank-users/cerrors"
 	"x-bank-users/ercodes"
+	"x-bank-users/tracing"
 )
 
+var tracer = otel.Tracer("x-bank-users/infra/hasher")
+
 type (
 	Service struct {
 	}
@@ -16,19 +20,23 @@ func NewService() Service {
 	return Service{}
 }
 
-func (s *Service) HashPassword(_ context.Context, password []byte, cost int) ([]byte, error) {
+func (s *Service) HashPassword(ctx context.Context, password []byte, cost int) ([]byte, error) {
+	_, span := tracer.Start(ctx, "bcrypt.GenerateFromPassword")
 	passwordHash, err := bcrypt.GenerateFromPassword(password, cost)
+	tracing.End(span, err)
 	if err != nil {
-		return nil, cerrors.NewErrorWithUserMessage(ercodes.BcryptHashing, err, "Ошибка хэширования пароля")
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.BcryptHashing, err, "Ошибка хэширования пароля").WithKind(cerrors.KindInternal)
 	}
 
 	return passwordHash, nil
 }
 
-func (s *Service) CompareHashAndPassword(_ context.Context, password string, hashedPassword []byte) error {
+func (s *Service) CompareHashAndPassword(ctx context.Context, password string, hashedPassword []byte) error {
+	_, span := tracer.Start(ctx, "bcrypt.CompareHashAndPassword")
 	err := bcrypt.CompareHashAndPassword(hashedPassword, []byte(password))
+	span.End()
 	if err != nil {
-		return cerrors.NewErrorWithUserMessage(ercodes.WrongPassword, err, "Неверный логин или пароль")
+		return cerrors.NewErrorWithUserMessage(ercodes.WrongPassword, err, "Неверный логин или пароль").WithKind(cerrors.KindUnauthenticated)
 	}
 	return nil
 }
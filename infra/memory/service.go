@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"x-bank-users/entity"
+)
+
+type (
+	Service struct {
+		mu     sync.Mutex
+		events []entity.Event
+		err    error
+	}
+)
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Publish(_ context.Context, event entity.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *Service) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+func (s *Service) Events() []entity.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]entity.Event(nil), s.events...)
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	DBStatsProvider interface {
+		Stats() sql.DBStats
+	}
+
+	dbStatsCollector struct {
+		db DBStatsProvider
+
+		maxOpen           *prometheus.Desc
+		open              *prometheus.Desc
+		inUse             *prometheus.Desc
+		idle              *prometheus.Desc
+		waitCount         *prometheus.Desc
+		waitDuration      *prometheus.Desc
+		maxIdleClosed     *prometheus.Desc
+		maxIdleTimeClosed *prometheus.Desc
+		maxLifetimeClosed *prometheus.Desc
+	}
+)
+
+func (s *Service) RegisterDBStats(name string, db DBStatsProvider) error {
+	labels := prometheus.Labels{"db": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", metric), help, nil, labels)
+	}
+
+	return s.registry.Register(&dbStatsCollector{
+		db:                db,
+		maxOpen:           desc("max_open_connections", "Максимальное количество открытых соединений"),
+		open:              desc("open_connections", "Количество открытых соединений"),
+		inUse:             desc("in_use_connections", "Количество используемых соединений"),
+		idle:              desc("idle_connections", "Количество простаивающих соединений"),
+		waitCount:         desc("wait_count_total", "Количество ожиданий свободного соединения"),
+		waitDuration:      desc("wait_duration_seconds_total", "Суммарное время ожидания свободного соединения"),
+		maxIdleClosed:     desc("max_idle_closed_total", "Соединения, закрытые из-за SetMaxIdleConns"),
+		maxIdleTimeClosed: desc("max_idle_time_closed_total", "Соединения, закрытые из-за SetConnMaxIdleTime"),
+		maxLifetimeClosed: desc("max_lifetime_closed_total", "Соединения, закрытые из-за SetConnMaxLifetime"),
+	})
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxIdleTimeClosed
+	ch <- c.maxLifetimeClosed
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxIdleTimeClosed, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"x-bank-users/core/web"
+)
+
+type (
+	PasswordHasher struct {
+		next    web.PasswordHasher
+		metrics *Service
+	}
+)
+
+func NewPasswordHasher(next web.PasswordHasher, metrics *Service) PasswordHasher {
+	return PasswordHasher{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (h *PasswordHasher) HashPassword(ctx context.Context, b []byte, cost int) ([]byte, error) {
+	start := time.Now()
+	defer func() { h.metrics.bcryptDuration.WithLabelValues("hash").Observe(time.Since(start).Seconds()) }()
+	return h.next.HashPassword(ctx, b, cost)
+}
+
+func (h *PasswordHasher) CompareHashAndPassword(ctx context.Context, password string, hashedPassword []byte) error {
+	start := time.Now()
+	defer func() { h.metrics.bcryptDuration.WithLabelValues("compare").Observe(time.Since(start).Seconds()) }()
+	return h.next.CompareHashAndPassword(ctx, password, hashedPassword)
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+	"x-bank-users/core/web"
+)
+
+type (
+	TwoFactorCodeNotifier struct {
+		next     web.TwoFactorCodeNotifier
+		notifier string
+		metrics  *Service
+	}
+)
+
+func NewTwoFactorCodeNotifier(next web.TwoFactorCodeNotifier, notifier string, metrics *Service) TwoFactorCodeNotifier {
+	return TwoFactorCodeNotifier{
+		next:     next,
+		notifier: notifier,
+		metrics:  metrics,
+	}
+}
+
+func (n *TwoFactorCodeNotifier) Send2FaCode(ctx context.Context, telegramId int64, code string) error {
+	err := n.next.Send2FaCode(ctx, telegramId, code)
+	n.metrics.observeNotifier(n.notifier, "Send2FaCode", err)
+	return err
+}
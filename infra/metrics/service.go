@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"x-bank-users/cerrors"
+	"x-bank-users/core/scheduler"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const namespace = "bank_users"
+
+type (
+	Service struct {
+		registry *prometheus.Registry
+
+		httpRequests        *prometheus.CounterVec
+		httpRequestDuration *prometheus.HistogramVec
+
+		authOutcomes *prometheus.CounterVec
+
+		bcryptDuration *prometheus.HistogramVec
+
+		storageDuration *prometheus.HistogramVec
+		storageErrors   *prometheus.CounterVec
+
+		notifierFailures *prometheus.CounterVec
+
+		jobRuns        *prometheus.CounterVec
+		jobDuration    *prometheus.HistogramVec
+		jobLastSuccess *prometheus.GaugeVec
+
+		deletedUsers        *prometheus.CounterVec
+		archivedAuthHistory prometheus.Counter
+	}
+)
+
+func NewService() Service {
+	s := Service{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Количество обработанных HTTP-запросов",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Время обработки HTTP-запросов",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		authOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_outcomes_total",
+			Help:      "Результаты входа, подтверждения 2FA и обновления токенов",
+		}, []string{"flow", "outcome"}),
+		bcryptDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "bcrypt_duration_seconds",
+			Help:      "Время хэширования и проверки паролей",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 10),
+		}, []string{"operation"}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_call_duration_seconds",
+			Help:      "Время вызовов хранилищ",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"backend", "method"}),
+		storageErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_call_errors_total",
+			Help:      "Количество ошибок вызовов хранилищ",
+		}, []string{"backend", "method", "kind"}),
+		notifierFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifier_failures_total",
+			Help:      "Количество неудачных отправок уведомлений",
+		}, []string{"notifier", "method"}),
+		jobRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "job_runs_total",
+			Help:      "Количество запусков фоновых задач",
+		}, []string{"job", "status"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_duration_seconds",
+			Help:      "Время выполнения фоновых задач",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+		}, []string{"job"}),
+		jobLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "job_last_success_timestamp_seconds",
+			Help:      "Время последнего успешного выполнения фоновой задачи",
+		}, []string{"job"}),
+		deletedUsers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cleaner_deleted_users_total",
+			Help:      "Количество пользователей, удалённых фоновыми задачами очистки",
+		}, []string{"job"}),
+		archivedAuthHistory: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cleaner_archived_auth_history_total",
+			Help:      "Количество записей истории входов, перенесённых в архив",
+		}),
+	}
+
+	s.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		s.httpRequests,
+		s.httpRequestDuration,
+		s.authOutcomes,
+		s.bcryptDuration,
+		s.storageDuration,
+		s.storageErrors,
+		s.notifierFailures,
+		s.jobRuns,
+		s.jobDuration,
+		s.jobLastSuccess,
+		s.deletedUsers,
+		s.archivedAuthHistory,
+	)
+
+	return s
+}
+
+func (s *Service) Handler() http.Handler {
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{Registry: s.registry})
+}
+
+func (s *Service) Push(ctx context.Context, url, job string) error {
+	return push.New(url, job).Gatherer(s.registry).PushContext(ctx)
+}
+
+func (s *Service) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if _, path, ok := strings.Cut(route, " "); ok {
+		route = path
+	}
+	if route == "" {
+		route = "unmatched"
+	}
+	statusLabel := strconv.Itoa(status)
+	s.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	s.httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+func (s *Service) ObserveAuth(flow, outcome string) {
+	s.authOutcomes.WithLabelValues(flow, outcome).Inc()
+}
+
+func (s *Service) ObserveJobRun(job, status string, duration time.Duration) {
+	s.jobRuns.WithLabelValues(job, status).Inc()
+	if status == scheduler.StatusSkipped {
+		return
+	}
+
+	s.jobDuration.WithLabelValues(job).Observe(duration.Seconds())
+	if status == scheduler.StatusSuccess {
+		s.jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
+
+func (s *Service) ObserveDeletedUsers(job string, count int64) {
+	s.deletedUsers.WithLabelValues(job).Add(float64(count))
+}
+
+func (s *Service) ObserveArchivedAuthHistory(count int64) {
+	s.archivedAuthHistory.Add(float64(count))
+}
+
+func (s *Service) observeStorage(backend, method string, start time.Time, err error) {
+	s.storageDuration.WithLabelValues(backend, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.storageErrors.WithLabelValues(backend, method, errorKind(err)).Inc()
+	}
+}
+
+func (s *Service) observeNotifier(notifier, method string, err error) {
+	if err != nil {
+		s.notifierFailures.WithLabelValues(notifier, method).Inc()
+	}
+}
+
+func errorKind(err error) string {
+	var cErr *cerrors.Error
+	if !errors.As(err, &cErr) {
+		return "unknown"
+	}
+	return cerrors.KindOf(err).String()
+}
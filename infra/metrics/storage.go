@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"x-bank-users/core/web"
+	"x-bank-users/entity"
+)
+
+type (
+	UserStorage struct {
+		next    web.UserStorage
+		backend string
+		metrics *Service
+	}
+
+	RefreshTokenStorage struct {
+		next    web.RefreshTokenStorage
+		backend string
+		metrics *Service
+	}
+)
+
+func NewUserStorage(next web.UserStorage, backend string, metrics *Service) UserStorage {
+	return UserStorage{
+		next:    next,
+		backend: backend,
+		metrics: metrics,
+	}
+}
+
+func NewRefreshTokenStorage(next web.RefreshTokenStorage, backend string, metrics *Service) RefreshTokenStorage {
+	return RefreshTokenStorage{
+		next:    next,
+		backend: backend,
+		metrics: metrics,
+	}
+}
+
+func (s *UserStorage) CreateUser(ctx context.Context, login, email string, passwordHash []byte) (int64, error) {
+	start := time.Now()
+	res, err := s.next.CreateUser(ctx, login, email, passwordHash)
+	s.metrics.observeStorage(s.backend, "CreateUser", start, err)
+	return res, err
+}
+
+func (s *UserStorage) GetSignInDataByLogin(ctx context.Context, login string) (web.UserDataToSignIn, error) {
+	start := time.Now()
+	res, err := s.next.GetSignInDataByLogin(ctx, login)
+	s.metrics.observeStorage(s.backend, "GetSignInDataByLogin", start, err)
+	return res, err
+}
+
+func (s *UserStorage) GetSignInDataById(ctx context.Context, id int64) (web.UserDataToSignIn, error) {
+	start := time.Now()
+	res, err := s.next.GetSignInDataById(ctx, id)
+	s.metrics.observeStorage(s.backend, "GetSignInDataById", start, err)
+	return res, err
+}
+
+func (s *UserStorage) UserIdByLoginAndEmail(ctx context.Context, login, email string) (int64, error) {
+	start := time.Now()
+	res, err := s.next.UserIdByLoginAndEmail(ctx, login, email)
+	s.metrics.observeStorage(s.backend, "UserIdByLoginAndEmail", start, err)
+	return res, err
+}
+
+func (s *UserStorage) UpdatePassword(ctx context.Context, id int64, passwordHash []byte) error {
+	start := time.Now()
+	err := s.next.UpdatePassword(ctx, id, passwordHash)
+	s.metrics.observeStorage(s.backend, "UpdatePassword", start, err)
+	return err
+}
+
+func (s *UserStorage) UpdateTelegramId(ctx context.Context, telegramId *int64, userId int64) error {
+	start := time.Now()
+	err := s.next.UpdateTelegramId(ctx, telegramId, userId)
+	s.metrics.observeStorage(s.backend, "UpdateTelegramId", start, err)
+	return err
+}
+
+func (s *UserStorage) GetUserPersonalDataById(ctx context.Context, userId int64) (*web.UserPersonalData, error) {
+	start := time.Now()
+	res, err := s.next.GetUserPersonalDataById(ctx, userId)
+	s.metrics.observeStorage(s.backend, "GetUserPersonalDataById", start, err)
+	return res, err
+}
+
+func (s *UserStorage) AddUserPersonalDataById(ctx context.Context, userId int64, data entity.UserPersonalData) error {
+	start := time.Now()
+	err := s.next.AddUserPersonalDataById(ctx, userId, data)
+	s.metrics.observeStorage(s.backend, "AddUserPersonalDataById", start, err)
+	return err
+}
+
+func (s *UserStorage) UpdateUserPersonalDataById(ctx context.Context, userId int64, data entity.UserPersonalData) error {
+	start := time.Now()
+	err := s.next.UpdateUserPersonalDataById(ctx, userId, data)
+	s.metrics.observeStorage(s.backend, "UpdateUserPersonalDataById", start, err)
+	return err
+}
+
+func (s *UserStorage) GetUserDataById(ctx context.Context, id int64) (web.UserData, error) {
+	start := time.Now()
+	res, err := s.next.GetUserDataById(ctx, id)
+	s.metrics.observeStorage(s.backend, "GetUserDataById", start, err)
+	return res, err
+}
+
+func (s *UserStorage) AddUsersAuthHistory(ctx context.Context, userId int64, agent, ip string) error {
+	start := time.Now()
+	err := s.next.AddUsersAuthHistory(ctx, userId, agent, ip)
+	s.metrics.observeStorage(s.backend, "AddUsersAuthHistory", start, err)
+	return err
+}
+
+func (s *UserStorage) GetUserAuthHistory(ctx context.Context, userId int64) ([]web.UserAuthHistoryData, error) {
+	start := time.Now()
+	res, err := s.next.GetUserAuthHistory(ctx, userId)
+	s.metrics.observeStorage(s.backend, "GetUserAuthHistory", start, err)
+	return res, err
+}
+
+func (s *UserStorage) GetUserWorkplaces(ctx context.Context, userId int64) ([]entity.UserWorkplace, error) {
+	start := time.Now()
+	res, err := s.next.GetUserWorkplaces(ctx, userId)
+	s.metrics.observeStorage(s.backend, "GetUserWorkplaces", start, err)
+	return res, err
+}
+
+func (s *UserStorage) AddUserWorkplace(ctx context.Context, userId int64, work entity.Workplace) (int64, error) {
+	start := time.Now()
+	res, err := s.next.AddUserWorkplace(ctx, userId, work)
+	s.metrics.observeStorage(s.backend, "AddUserWorkplace", start, err)
+	return res, err
+}
+
+func (s *UserStorage) UpdateUserWorkplace(ctx context.Context, userId, id int64, work entity.Workplace) error {
+	start := time.Now()
+	err := s.next.UpdateUserWorkplace(ctx, userId, id, work)
+	s.metrics.observeStorage(s.backend, "UpdateUserWorkplace", start, err)
+	return err
+}
+
+func (s *UserStorage) DeleteUserWorkplace(ctx context.Context, userId, id int64) error {
+	start := time.Now()
+	err := s.next.DeleteUserWorkplace(ctx, userId, id)
+	s.metrics.observeStorage(s.backend, "DeleteUserWorkplace", start, err)
+	return err
+}
+
+func (s *UserStorage) ScheduleUserDeletion(ctx context.Context, userId int64, deleteAt time.Time) error {
+	start := time.Now()
+	err := s.next.ScheduleUserDeletion(ctx, userId, deleteAt)
+	s.metrics.observeStorage(s.backend, "ScheduleUserDeletion", start, err)
+	return err
+}
+
+func (s *UserStorage) CancelUserDeletion(ctx context.Context, userId int64) error {
+	start := time.Now()
+	err := s.next.CancelUserDeletion(ctx, userId)
+	s.metrics.observeStorage(s.backend, "CancelUserDeletion", start, err)
+	return err
+}
+
+func (s *UserStorage) LockUserById(ctx context.Context, userId int64) error {
+	start := time.Now()
+	err := s.next.LockUserById(ctx, userId)
+	s.metrics.observeStorage(s.backend, "LockUserById", start, err)
+	return err
+}
+
+func (s *UserStorage) ActivateUser(ctx context.Context, userId int64) error {
+	start := time.Now()
+	err := s.next.ActivateUser(ctx, userId)
+	s.metrics.observeStorage(s.backend, "ActivateUser", start, err)
+	return err
+}
+
+func (s *RefreshTokenStorage) SaveRefreshToken(ctx context.Context, token string, userId int64, ttl time.Duration) error {
+	start := time.Now()
+	err := s.next.SaveRefreshToken(ctx, token, userId, ttl)
+	s.metrics.observeStorage(s.backend, "SaveRefreshToken", start, err)
+	return err
+}
+
+func (s *RefreshTokenStorage) VerifyRefreshToken(ctx context.Context, token string) (int64, error) {
+	start := time.Now()
+	res, err := s.next.VerifyRefreshToken(ctx, token)
+	s.metrics.observeStorage(s.backend, "VerifyRefreshToken", start, err)
+	return res, err
+}
+
+func (s *RefreshTokenStorage) ExpireAllByUserId(ctx context.Context, userId int64) error {
+	start := time.Now()
+	err := s.next.ExpireAllByUserId(ctx, userId)
+	s.metrics.observeStorage(s.backend, "ExpireAllByUserId", start, err)
+	return err
+}
+
+func (s *RefreshTokenStorage) GetSessionsByUserId(ctx context.Context, userId int64) ([]web.UserSession, error) {
+	start := time.Now()
+	res, err := s.next.GetSessionsByUserId(ctx, userId)
+	s.metrics.observeStorage(s.backend, "GetSessionsByUserId", start, err)
+	return res, err
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"strings"
+	"x-bank-users/core/web"
+)
+
+func (s *Service) LogEvent(ctx context.Context, event web.AuditEvent) error {
+	const query = `
+INSERT INTO audit_events ("actorId", "subjectId", action, before, after, details, "agent", ip, "requestId")
+VALUES (@actorId, @subjectId, @action, @before, @after, @details, NULLIF(@agent, ''), NULLIF(@ip, '')::inet, NULLIF(@requestId, ''))`
+
+	meta := web.RequestMetaFromContext(ctx)
+	if event.ActorId == nil {
+		event.ActorId = meta.ActorId
+	}
+	if event.Agent == "" {
+		event.Agent = meta.Agent
+	}
+	if event.Ip == "" {
+		event.Ip = meta.Ip
+	}
+	if event.RequestId == "" {
+		event.RequestId = meta.RequestId
+	}
+
+	before, err := marshalAuditValues(event.Before)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+	after, err := marshalAuditValues(event.After)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+	details, err := marshalAuditValues(event.Details)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	_, err = s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"actorId":   event.ActorId,
+		"subjectId": event.SubjectId,
+		"action":    event.Action,
+		"before":    before,
+		"after":     after,
+		"details":   details,
+		"agent":     event.Agent,
+		"ip":        event.Ip,
+		"requestId": event.RequestId,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetAuditEvents(ctx context.Context, filter web.AuditEventsFilter) ([]web.AuditEvent, error) {
+	conditions := []string{"TRUE"}
+	args := pgx.NamedArgs{
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	}
+
+	if filter.ActorId != nil {
+		conditions = append(conditions, `"actorId" = @actorId`)
+		args["actorId"] = *filter.ActorId
+	}
+	if filter.SubjectId != nil {
+		conditions = append(conditions, `"subjectId" = @subjectId`)
+		args["subjectId"] = *filter.SubjectId
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, `action = @action`)
+		args["action"] = filter.Action
+	}
+	if filter.From != nil {
+		conditions = append(conditions, `"createdAt" >= @from`)
+		args["from"] = *filter.From
+	}
+	if filter.To != nil {
+		conditions = append(conditions, `"createdAt" < @to`)
+		args["to"] = *filter.To
+	}
+
+	query := `
+SELECT id, "actorId", "subjectId", action, before, after, details, COALESCE("agent", ''), COALESCE(host(ip), ''), COALESCE("requestId", ''), "createdAt"
+FROM audit_events
+WHERE ` + strings.Join(conditions, " AND ") + `
+ORDER BY "createdAt" DESC, id DESC
+LIMIT @limit OFFSET @offset`
+
+	rows, err := s.querier(ctx).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []web.AuditEvent
+	for rows.Next() {
+		var event web.AuditEvent
+		var before, after, details []byte
+		err = rows.Scan(&event.Id, &event.ActorId, &event.SubjectId, &event.Action, &before, &after, &details,
+			&event.Agent, &event.Ip, &event.RequestId, &event.CreatedAt)
+		if err != nil {
+			return nil, s.wrapScanError(err)
+		}
+
+		if event.Before, err = unmarshalAuditValues(before); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		if event.After, err = unmarshalAuditValues(after); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		if event.Details, err = unmarshalAuditValues(details); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return events, nil
+}
+
+func marshalAuditValues(values map[string]string) ([]byte, error) {
+	if values == nil {
+		return nil, nil
+	}
+	return json.Marshal(values)
+}
+
+func unmarshalAuditValues(data []byte) (map[string]string, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"strings"
+	"time"
+	"x-bank-users/core/cleaner"
+)
+
+const (
+	authHistoryTable            = "users_auth_history"
+	authHistoryDefaultPartition = "users_auth_history_default"
+	authHistoryPartitionPrefix  = "users_auth_history_"
+	authHistoryPartitionLayout  = "2006_01"
+)
+
+func authHistoryPartitionName(month time.Time) string {
+	return authHistoryPartitionPrefix + month.Format(authHistoryPartitionLayout)
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func (s *Service) EnsureAuthHistoryPartitions(ctx context.Context, from, to time.Time) error {
+	for month := monthStart(from); month.Before(to); month = month.AddDate(0, 1, 0) {
+		if err := s.ensureAuthHistoryPartition(ctx, month); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ensureAuthHistoryPartition(ctx context.Context, month time.Time) error {
+	name := authHistoryPartitionName(month)
+	from := month.Format(time.DateOnly)
+	to := month.AddDate(0, 1, 0).Format(time.DateOnly)
+
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		var exists bool
+		if err := s.querier(ctx).QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+			return s.wrapScanError(err)
+		}
+		if exists {
+			return nil
+		}
+
+		partition := pgx.Identifier{name}.Sanitize()
+		queries := []string{
+			fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, partition, authHistoryTable),
+			fmt.Sprintf(`
+WITH moved AS (DELETE FROM %s WHERE timestamp >= '%s' AND timestamp < '%s' RETURNING id, "userId", "agent", ip, timestamp)
+INSERT INTO %s (id, "userId", "agent", ip, timestamp) SELECT * FROM moved`, authHistoryDefaultPartition, from, to, partition),
+			fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`, authHistoryTable, partition, from, to),
+		}
+		for _, query := range queries {
+			if _, err := s.querier(ctx).ExecContext(ctx, query); err != nil {
+				return s.wrapQueryError(err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Service) CountAuthHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
+	const query = `SELECT count(*) FROM users_auth_history WHERE timestamp < $1`
+
+	var count int64
+	if err := s.querier(ctx).QueryRowContext(ctx, query, before).Scan(&count); err != nil {
+		return 0, s.wrapScanError(err)
+	}
+
+	return count, nil
+}
+
+func (s *Service) GetAuthHistoryBefore(ctx context.Context, before time.Time, limit int) ([]cleaner.AuthHistoryRecord, error) {
+	const query = `
+SELECT id, "userId", "agent", ip, timestamp FROM users_auth_history
+WHERE timestamp < $1
+ORDER BY timestamp, id
+LIMIT $2`
+
+	rows, err := s.querier(ctx).QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var records []cleaner.AuthHistoryRecord
+	for rows.Next() {
+		var record cleaner.AuthHistoryRecord
+		if err = rows.Scan(&record.Id, &record.UserId, &record.Agent, &record.Ip, &record.Timestamp); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		records = append(records, record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return records, nil
+}
+
+func (s *Service) DeleteAuthHistory(ctx context.Context, ids []int64, before time.Time) error {
+	const query = `DELETE FROM users_auth_history WHERE id = ANY($1) AND timestamp < $2`
+
+	if _, err := s.querier(ctx).ExecContext(ctx, query, ids, before); err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) DropAuthHistoryPartitions(ctx context.Context, before time.Time) ([]string, error) {
+	const query = `
+SELECT c.relname FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = 'users_auth_history'::regclass
+ORDER BY c.relname`
+
+	names, err := s.selectNames(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, name := range names {
+		month, err := time.ParseInLocation(authHistoryPartitionLayout, strings.TrimPrefix(name, authHistoryPartitionPrefix), before.Location())
+		if err != nil || month.AddDate(0, 1, 0).After(before) {
+			continue
+		}
+
+		partition := pgx.Identifier{name}.Sanitize()
+		var hasRows bool
+		if err = s.querier(ctx).QueryRowContext(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, partition)).Scan(&hasRows); err != nil {
+			return dropped, s.wrapScanError(err)
+		}
+		if hasRows {
+			continue
+		}
+
+		if _, err = s.querier(ctx).ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s`, partition)); err != nil {
+			return dropped, s.wrapQueryError(err)
+		}
+		dropped = append(dropped, name)
+	}
+
+	return dropped, nil
+}
+
+func (s *Service) selectNames(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := s.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		names = append(names, name)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return names, nil
+}
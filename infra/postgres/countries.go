@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"x-bank-users/entity"
+)
+
+func (s *Service) GetCountries(ctx context.Context) ([]entity.Country, error) {
+	const query = `
+SELECT id, code, alpha3, name, "nameEn", "phonePrefix"
+FROM countries
+ORDER BY name`
+
+	rows, err := s.querier(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var countries []entity.Country
+	for rows.Next() {
+		var country entity.Country
+		if err = rows.Scan(&country.Id, &country.Alpha2, &country.Alpha3, &country.NameRu, &country.NameEn, &country.PhonePrefix); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		countries = append(countries, country)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return countries, nil
+}
@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strconv"
+	"time"
+	"x-bank-users/cerrors"
+	"x-bank-users/entity"
+	"x-bank-users/ercodes"
+)
+
+type (
+	encryptedPersonalData struct {
+		PhoneNumber    []byte
+		DateOfBirth    []byte
+		PassportId     []byte
+		Address        []byte
+		PassportIdHash []byte
+		DataKey        []byte
+		KeyId          *string
+	}
+
+	plainPersonalData struct {
+		PhoneNumber string
+		DateOfBirth string
+		PassportId  string
+		Address     string
+	}
+)
+
+func (s *Service) encryptPersonalData(ctx context.Context, id int64, data entity.UserPersonalData) (encryptedPersonalData, error) {
+	dateOfBirth, err := time.Parse(time.DateOnly, data.DateOfBirth)
+	if err != nil {
+		return encryptedPersonalData{}, s.wrapQueryError(err)
+	}
+
+	return s.encryptPlainPersonalData(ctx, id, plainPersonalData{
+		PhoneNumber: data.PhoneNumber,
+		DateOfBirth: dateOfBirth.Format(time.DateOnly),
+		PassportId:  data.PassportId,
+		Address:     data.Address,
+	})
+}
+
+func (s *Service) encryptPlainPersonalData(ctx context.Context, id int64, data plainPersonalData) (encryptedPersonalData, error) {
+	dataKey, keyId, wrappedKey, err := s.encryptor.NewDataKey(ctx)
+	if err != nil {
+		return encryptedPersonalData{}, err
+	}
+
+	fields := encryptedPersonalData{
+		PassportIdHash: s.encryptor.BlindIndex(data.PassportId),
+		DataKey:        wrappedKey,
+		KeyId:          &keyId,
+	}
+
+	values := []struct {
+		dst       *[]byte
+		column    string
+		plaintext string
+	}{
+		{&fields.PhoneNumber, "phoneNumber", data.PhoneNumber},
+		{&fields.DateOfBirth, "dateOfBirth", data.DateOfBirth},
+		{&fields.PassportId, "passportId", data.PassportId},
+		{&fields.Address, "address", data.Address},
+	}
+	for _, v := range values {
+		if *v.dst, err = s.encryptor.Encrypt(dataKey, v.plaintext, personalDataAAD(id, v.column)); err != nil {
+			return encryptedPersonalData{}, err
+		}
+	}
+
+	return fields, nil
+}
+
+func (s *Service) decryptPersonalData(ctx context.Context, id int64, fields encryptedPersonalData) (plainPersonalData, error) {
+	if fields.DataKey == nil || fields.KeyId == nil {
+		return plainPersonalData{
+			PhoneNumber: string(fields.PhoneNumber),
+			DateOfBirth: string(fields.DateOfBirth),
+			PassportId:  string(fields.PassportId),
+			Address:     string(fields.Address),
+		}, nil
+	}
+
+	dataKey, err := s.encryptor.UnwrapDataKey(ctx, *fields.KeyId, fields.DataKey)
+	if err != nil {
+		return plainPersonalData{}, err
+	}
+
+	var data plainPersonalData
+	values := []struct {
+		dst        *string
+		column     string
+		ciphertext []byte
+	}{
+		{&data.PhoneNumber, "phoneNumber", fields.PhoneNumber},
+		{&data.DateOfBirth, "dateOfBirth", fields.DateOfBirth},
+		{&data.PassportId, "passportId", fields.PassportId},
+		{&data.Address, "address", fields.Address},
+	}
+	for _, v := range values {
+		if *v.dst, err = s.encryptor.Decrypt(dataKey, v.ciphertext, personalDataAAD(id, v.column)); err != nil {
+			return plainPersonalData{}, err
+		}
+	}
+
+	return data, nil
+}
+
+func personalDataAAD(id int64, column string) []byte {
+	return []byte("users_personal_data/" + strconv.FormatInt(id, 10) + "/" + column)
+}
+
+func (s *Service) ReencryptPersonalData(ctx context.Context, batchSize int) (int64, error) {
+	return s.reencryptPersonalData(ctx, batchSize, false)
+}
+
+func (s *Service) EncryptLegacyPersonalData(ctx context.Context, batchSize int) (int64, error) {
+	return s.reencryptPersonalData(ctx, batchSize, true)
+}
+
+func (s *Service) reencryptPersonalData(ctx context.Context, batchSize int, legacyOnly bool) (int64, error) {
+	var total, lastId int64
+
+	for {
+		processed, nextId, err := s.reencryptPersonalDataBatch(ctx, lastId, batchSize, legacyOnly)
+		if err != nil {
+			return total, err
+		}
+
+		total += processed
+		if nextId == lastId {
+			return total, nil
+		}
+		lastId = nextId
+	}
+}
+
+func (s *Service) reencryptPersonalDataBatch(ctx context.Context, afterId int64, batchSize int, legacyOnly bool) (int64, int64, error) {
+	const query = `
+SELECT id, "phoneNumber", "dateOfBirth", "passportId", address, "dataKey", "keyId"
+FROM users_personal_data
+WHERE id > $1 AND "keyId" IS DISTINCT FROM $2 AND ("keyId" IS NULL OR NOT $4)
+ORDER BY id
+LIMIT $3
+FOR UPDATE`
+
+	const queryUpdate = `
+UPDATE users_personal_data
+SET "phoneNumber" = $1,
+    "dateOfBirth" = $2,
+    "passportId" = $3,
+    address = $4,
+    "passportIdHash" = $5,
+    "dataKey" = $6,
+    "keyId" = $7
+WHERE id = $8`
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, afterId, s.wrapQueryError(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, query, afterId, s.encryptor.CurrentKeyId(), batchSize, legacyOnly)
+	if err != nil {
+		return 0, afterId, s.wrapQueryError(err)
+	}
+
+	type row struct {
+		id     int64
+		fields encryptedPersonalData
+	}
+	var batch []row
+	for rows.Next() {
+		var r row
+		if err = rows.Scan(&r.id, &r.fields.PhoneNumber, &r.fields.DateOfBirth, &r.fields.PassportId, &r.fields.Address, &r.fields.DataKey, &r.fields.KeyId); err != nil {
+			_ = rows.Close()
+			return 0, afterId, s.wrapScanError(err)
+		}
+		batch = append(batch, r)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, afterId, s.wrapQueryError(err)
+	}
+
+	lastId := afterId
+	for _, r := range batch {
+		plain, err := s.decryptPersonalData(ctx, r.id, r.fields)
+		if err != nil {
+			return 0, afterId, err
+		}
+
+		fields, err := s.encryptPlainPersonalData(ctx, r.id, plain)
+		if err != nil {
+			return 0, afterId, err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdate, fields.PhoneNumber, fields.DateOfBirth, fields.PassportId, fields.Address,
+			fields.PassportIdHash, fields.DataKey, fields.KeyId, r.id)
+		if err != nil {
+			return 0, afterId, s.wrapPersonalDataError(err)
+		}
+		lastId = r.id
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, afterId, s.wrapQueryError(err)
+	}
+
+	return int64(len(batch)), lastId, nil
+}
+
+func (s *Service) wrapPersonalDataError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == uniquePassportIdConstraint {
+		return cerrors.NewErrorWithUserMessage(ercodes.PassportAlreadyTaken, nil, "Паспорт уже используется").WithKind(cerrors.KindConflict)
+	}
+	return s.wrapQueryError(err)
+}
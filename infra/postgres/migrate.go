@@ -0,0 +1,325 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"x-bank-users/logging"
+)
+
+const (
+	migrationsLockKey = 7281530114
+)
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type (
+	Migration struct {
+		Version int64
+		Name    string
+
+		up   string
+		down string
+	}
+
+	MigrationState struct {
+		Version int64
+		Name    string
+		Applied bool
+	}
+
+	MigrationStatus struct {
+		Version    int64
+		Dirty      bool
+		Migrations []MigrationState
+	}
+)
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("миграция %d: различаются имена %q и %q", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" {
+			return nil, fmt.Errorf("миграция %d_%s: отсутствует up-файл", migration.Version, migration.Name)
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+
+	return result, nil
+}
+
+func (s *Service) withMigrationLock(ctx context.Context, fn func(conn *sql.Conn, migrations []Migration) error) error {
+	migrations, err := loadMigrations(s.migrations)
+	if err != nil {
+		return err
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, int64(migrationsLockKey)); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, int64(migrationsLockKey))
+	}()
+
+	const query = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+	if _, err = conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn(conn, migrations)
+}
+
+func currentMigrationVersion(ctx context.Context, q querier) (int64, bool, error) {
+	const query = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+	var version int64
+	var dirty bool
+	err := q.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+func setMigrationVersion(ctx context.Context, q querier, version int64, dirty bool) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+
+	_, err := q.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty)
+	return err
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, migration Migration, script string, version int64) error {
+	if err := setMigrationVersion(ctx, conn, migration.Version, true); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err = setMigrationVersion(ctx, tx, version, false); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Service) MigrateUp(ctx context.Context, steps int) ([]Migration, error) {
+	var applied []Migration
+	err := s.withMigrationLock(ctx, func(conn *sql.Conn, migrations []Migration) error {
+		version, dirty, err := currentMigrationVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("база данных в состоянии dirty на версии %d, требуется force", version)
+		}
+		if version == 0 {
+			exists, err := schemaExists(ctx, conn)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return errors.New("схема создана без истории миграций, требуется baseline")
+			}
+		}
+
+		for _, migration := range migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if steps > 0 && len(applied) == steps {
+				break
+			}
+
+			if err = applyMigration(ctx, conn, migration, migration.up, migration.Version); err != nil {
+				return fmt.Errorf("миграция %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logging.FromContext(ctx).Info("migration applied", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+func (s *Service) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := s.withMigrationLock(ctx, func(conn *sql.Conn, migrations []Migration) error {
+		version, dirty, err := currentMigrationVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("база данных в состоянии dirty на версии %d, требуется force", version)
+		}
+
+		for i := len(migrations) - 1; i >= 0; i-- {
+			migration := migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			if steps > 0 && len(reverted) == steps {
+				break
+			}
+			if migration.down == "" {
+				return fmt.Errorf("миграция %d_%s: отсутствует down-файл", migration.Version, migration.Name)
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = migrations[i-1].Version
+			}
+
+			if err = applyMigration(ctx, conn, migration, migration.down, previous); err != nil {
+				return fmt.Errorf("миграция %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logging.FromContext(ctx).Info("migration reverted", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+func (s *Service) ForceMigrationVersion(ctx context.Context, version int64) error {
+	return s.withMigrationLock(ctx, func(conn *sql.Conn, migrations []Migration) error {
+		known := version == 0
+		for _, migration := range migrations {
+			if migration.Version == version {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("неизвестная версия миграции %d", version)
+		}
+
+		return setMigrationVersion(ctx, conn, version, false)
+	})
+}
+
+func (s *Service) BaselineMigrations(ctx context.Context) (Migration, error) {
+	var baseline Migration
+	err := s.withMigrationLock(ctx, func(conn *sql.Conn, migrations []Migration) error {
+		version, _, err := currentMigrationVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if version != 0 {
+			return fmt.Errorf("история миграций уже ведётся, текущая версия %d", version)
+		}
+
+		exists, err := schemaExists(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("схема не найдена, используйте up")
+		}
+		if len(migrations) == 0 {
+			return errors.New("нет миграций")
+		}
+
+		baseline = migrations[0]
+		return setMigrationVersion(ctx, conn, baseline.Version, false)
+	})
+
+	return baseline, err
+}
+
+func schemaExists(ctx context.Context, q querier) (bool, error) {
+	const query = `SELECT to_regclass('users') IS NOT NULL`
+
+	var exists bool
+	err := q.QueryRowContext(ctx, query).Scan(&exists)
+
+	return exists, err
+}
+
+func (s *Service) MigrationStatus(ctx context.Context) (MigrationStatus, error) {
+	var status MigrationStatus
+	err := s.withMigrationLock(ctx, func(conn *sql.Conn, migrations []Migration) error {
+		version, dirty, err := currentMigrationVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		status.Version = version
+		status.Dirty = dirty
+		for _, migration := range migrations {
+			status.Migrations = append(status.Migrations, MigrationState{
+				Version: migration.Version,
+				Name:    migration.Name,
+				Applied: migration.Version <= version,
+			})
+		}
+		return nil
+	})
+
+	return status, err
+}
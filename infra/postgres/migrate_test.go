@@ -0,0 +1,446 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"x-bank-users/infra/postgres/migrations"
+)
+
+type (
+	fakeMigrationDB struct {
+		advisoryLock sync.Mutex
+
+		mu      sync.Mutex
+		state   fakeMigrationState
+		schema  bool
+		failOn  string
+		scripts []string
+		log     []string
+	}
+
+	fakeMigrationState struct {
+		version int64
+		dirty   bool
+		hasRow  bool
+	}
+
+	fakeMigrationConnector struct {
+		db *fakeMigrationDB
+	}
+
+	fakeMigrationConn struct {
+		db       *fakeMigrationDB
+		snapshot *fakeMigrationState
+	}
+
+	fakeMigrationRows struct {
+		columns []string
+		values  [][]driver.Value
+	}
+)
+
+func (c fakeMigrationConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeMigrationConn{db: c.db}, nil
+}
+
+func (c fakeMigrationConnector) Driver() driver.Driver {
+	return nil
+}
+
+func (c *fakeMigrationConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *fakeMigrationConn) Close() error {
+	return nil
+}
+
+func (c *fakeMigrationConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeMigrationConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	snapshot := c.db.state
+	c.snapshot = &snapshot
+	return c, nil
+}
+
+func (c *fakeMigrationConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeMigrationConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	if c.snapshot != nil {
+		c.db.state = *c.snapshot
+		c.snapshot = nil
+	}
+	return nil
+}
+
+func (c *fakeMigrationConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	switch {
+	case strings.HasPrefix(query, "SELECT pg_advisory_lock"):
+		c.db.advisoryLock.Lock()
+		c.db.record("lock")
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(query, "SELECT pg_advisory_unlock"):
+		c.db.record("unlock")
+		c.db.advisoryLock.Unlock()
+		return driver.RowsAffected(0), nil
+	}
+
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations"):
+		c.db.state = fakeMigrationState{}
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		c.db.state = fakeMigrationState{version: args[0].Value.(int64), dirty: args[1].Value.(bool), hasRow: true}
+	default:
+		if c.db.failOn != "" && strings.Contains(query, c.db.failOn) {
+			return nil, errors.New("syntax error")
+		}
+		c.db.scripts = append(c.db.scripts, query)
+	}
+
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeMigrationConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT version, dirty FROM schema_migrations"):
+		rows := &fakeMigrationRows{columns: []string{"version", "dirty"}}
+		if c.db.state.hasRow {
+			rows.values = append(rows.values, []driver.Value{c.db.state.version, c.db.state.dirty})
+		}
+		return rows, nil
+	case strings.HasPrefix(query, "SELECT to_regclass"):
+		return &fakeMigrationRows{columns: []string{"exists"}, values: [][]driver.Value{{c.db.schema}}}, nil
+	}
+
+	return nil, errors.New("unexpected query: " + query)
+}
+
+func (r *fakeMigrationRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeMigrationRows) Close() error {
+	return nil
+}
+
+func (r *fakeMigrationRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func (db *fakeMigrationDB) record(entry string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.log = append(db.log, entry)
+}
+
+func (db *fakeMigrationDB) current() fakeMigrationState {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.state
+}
+
+var testMigrations = fstest.MapFS{
+	"9_users.up.sql":       {Data: []byte("CREATE TABLE users ()")},
+	"9_users.down.sql":     {Data: []byte("DROP TABLE users")},
+	"10_orders.up.sql":     {Data: []byte("CREATE TABLE orders ()")},
+	"10_orders.down.sql":   {Data: []byte("DROP TABLE orders")},
+	"100_refunds.up.sql":   {Data: []byte("CREATE TABLE refunds ()")},
+	"100_refunds.down.sql": {Data: []byte("DROP TABLE refunds")},
+	"README.md":            {Data: []byte("not a migration")},
+}
+
+func newMigrationService(db *fakeMigrationDB) Service {
+	return Service{
+		db:         sql.OpenDB(fakeMigrationConnector{db: db}),
+		migrations: testMigrations,
+	}
+}
+
+func migrationVersions(migrations []Migration) []int64 {
+	versions := make([]int64, 0, len(migrations))
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	loaded, err := loadMigrations(testMigrations)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	if got, want := migrationVersions(loaded), []int64{9, 10, 100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("versions = %v, want %v", got, want)
+	}
+	if loaded[1].Name != "orders" || loaded[1].up != "CREATE TABLE orders ()" || loaded[1].down != "DROP TABLE orders" {
+		t.Fatalf("migration 10 = %+v", loaded[1])
+	}
+}
+
+func TestLoadMigrationsRejectsBrokenSets(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "name mismatch",
+			fsys: fstest.MapFS{
+				"1_users.up.sql":      {Data: []byte("CREATE TABLE users ()")},
+				"1_accounts.down.sql": {Data: []byte("DROP TABLE users")},
+			},
+		},
+		{
+			name: "missing up",
+			fsys: fstest.MapFS{
+				"1_users.down.sql": {Data: []byte("DROP TABLE users")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		if _, err := loadMigrations(tt.fsys); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsAreReversible(t *testing.T) {
+	loaded, err := loadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for i, migration := range loaded {
+		if migration.down == "" {
+			t.Errorf("%d_%s: missing down file", migration.Version, migration.Name)
+		}
+		if i > 0 && migration.Version <= loaded[i-1].Version {
+			t.Errorf("%d_%s is not ordered after %d", migration.Version, migration.Name, loaded[i-1].Version)
+		}
+	}
+}
+
+func TestMigrateUpAppliesInOrderAndClearsDirty(t *testing.T) {
+	db := &fakeMigrationDB{}
+	service := newMigrationService(db)
+
+	applied, err := service.MigrateUp(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	if got, want := migrationVersions(applied), []int64{9, 10, 100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("applied = %v, want %v", got, want)
+	}
+	wantScripts := []string{"CREATE TABLE users ()", "CREATE TABLE orders ()", "CREATE TABLE refunds ()"}
+	if !reflect.DeepEqual(db.scripts, wantScripts) {
+		t.Fatalf("scripts = %q, want %q", db.scripts, wantScripts)
+	}
+	if got := db.current(); got.version != 100 || got.dirty {
+		t.Fatalf("state = %+v, want clean version 100", got)
+	}
+
+	applied, err = service.MigrateUp(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("second MigrateUp: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("second MigrateUp applied %v", migrationVersions(applied))
+	}
+}
+
+func TestMigrateUpHonoursSteps(t *testing.T) {
+	db := &fakeMigrationDB{}
+	service := newMigrationService(db)
+
+	applied, err := service.MigrateUp(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	if got, want := migrationVersions(applied), []int64{9, 10}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("applied = %v, want %v", got, want)
+	}
+	if got := db.current(); got.version != 10 {
+		t.Fatalf("version = %d, want 10", got.version)
+	}
+}
+
+func TestFailedMigrationLeavesDatabaseDirty(t *testing.T) {
+	db := &fakeMigrationDB{failOn: "orders"}
+	service := newMigrationService(db)
+	ctx := context.Background()
+
+	applied, err := service.MigrateUp(ctx, 0)
+	if err == nil {
+		t.Fatal("MigrateUp: expected error")
+	}
+	if got, want := migrationVersions(applied), []int64{9}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("applied = %v, want %v", got, want)
+	}
+	if got := db.current(); got.version != 10 || !got.dirty {
+		t.Fatalf("state = %+v, want dirty version 10", got)
+	}
+	if last := db.log[len(db.log)-1]; last != "unlock" {
+		t.Fatalf("last lock operation = %q, want unlock", last)
+	}
+
+	db.failOn = ""
+	if _, err = service.MigrateUp(ctx, 0); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Fatalf("MigrateUp on dirty database: %v, want dirty error", err)
+	}
+	if _, err = service.MigrateDown(ctx, 1); err == nil || !strings.Contains(err.Error(), "dirty") {
+		t.Fatalf("MigrateDown on dirty database: %v, want dirty error", err)
+	}
+
+	if err = service.ForceMigrationVersion(ctx, 9); err != nil {
+		t.Fatalf("ForceMigrationVersion: %v", err)
+	}
+	applied, err = service.MigrateUp(ctx, 0)
+	if err != nil {
+		t.Fatalf("MigrateUp after force: %v", err)
+	}
+	if got, want := migrationVersions(applied), []int64{10, 100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("applied after force = %v, want %v", got, want)
+	}
+}
+
+func TestMigrateDownRevertsToPreviousVersion(t *testing.T) {
+	db := &fakeMigrationDB{}
+	service := newMigrationService(db)
+	ctx := context.Background()
+
+	if _, err := service.MigrateUp(ctx, 0); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	reverted, err := service.MigrateDown(ctx, 2)
+	if err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if got, want := migrationVersions(reverted), []int64{100, 10}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("reverted = %v, want %v", got, want)
+	}
+	if got := db.current(); got.version != 9 || got.dirty {
+		t.Fatalf("state = %+v, want clean version 9", got)
+	}
+
+	if _, err = service.MigrateDown(ctx, 0); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if got := db.current(); got.hasRow {
+		t.Fatalf("state = %+v, want no version", got)
+	}
+}
+
+func TestBaselineMigrations(t *testing.T) {
+	db := &fakeMigrationDB{schema: true}
+	service := newMigrationService(db)
+	ctx := context.Background()
+
+	if _, err := service.MigrateUp(ctx, 0); err == nil || !strings.Contains(err.Error(), "baseline") {
+		t.Fatalf("MigrateUp on unversioned schema: %v, want baseline error", err)
+	}
+	if len(db.scripts) != 0 {
+		t.Fatalf("scripts ran on unversioned schema: %q", db.scripts)
+	}
+
+	baseline, err := service.BaselineMigrations(ctx)
+	if err != nil {
+		t.Fatalf("BaselineMigrations: %v", err)
+	}
+	if baseline.Version != 9 {
+		t.Fatalf("baseline = %d, want 9", baseline.Version)
+	}
+	if _, err = service.BaselineMigrations(ctx); err == nil {
+		t.Fatal("second BaselineMigrations: expected error")
+	}
+
+	applied, err := service.MigrateUp(ctx, 0)
+	if err != nil {
+		t.Fatalf("MigrateUp after baseline: %v", err)
+	}
+	if got, want := migrationVersions(applied), []int64{10, 100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("applied = %v, want %v", got, want)
+	}
+}
+
+func TestBaselineRequiresExistingSchema(t *testing.T) {
+	service := newMigrationService(&fakeMigrationDB{})
+
+	if _, err := service.BaselineMigrations(context.Background()); err == nil {
+		t.Fatal("BaselineMigrations on empty database: expected error")
+	}
+}
+
+func TestConcurrentMigrateUpAppliesOnce(t *testing.T) {
+	db := &fakeMigrationDB{}
+	service := newMigrationService(db)
+
+	var wg sync.WaitGroup
+	results := make([][]Migration, 4)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = service.MigrateUp(context.Background(), 0)
+		}(i)
+	}
+	wg.Wait()
+
+	var total int
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("MigrateUp %d: %v", i, err)
+		}
+		total += len(results[i])
+	}
+	if total != 3 {
+		t.Fatalf("applied %d migrations across replicas, want 3", total)
+	}
+	if len(db.scripts) != 3 {
+		t.Fatalf("scripts = %q, want each migration once", db.scripts)
+	}
+
+	for i := 0; i < len(db.log); i += 2 {
+		if db.log[i] != "lock" || db.log[i+1] != "unlock" {
+			t.Fatalf("lock operations interleave: %v", db.log)
+		}
+	}
+}
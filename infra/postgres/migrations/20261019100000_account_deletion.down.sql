@@ -0,0 +1,16 @@
+ALTER TABLE users_employments
+    DROP CONSTRAINT "users_employments_userId_fkey",
+    ADD CONSTRAINT "users_employments_userId_fkey" FOREIGN KEY ("userId") REFERENCES users (id);
+
+ALTER TABLE users_personal_data
+    DROP CONSTRAINT "users_personal_data_id_fkey",
+    ADD CONSTRAINT "users_personal_data_id_fkey" FOREIGN KEY (id) REFERENCES users (id);
+
+ALTER TABLE users_auth_history
+    DROP CONSTRAINT "users_auth_history_userId_fkey",
+    ADD CONSTRAINT "users_auth_history_userId_fkey" FOREIGN KEY ("userId") REFERENCES users (id);
+
+DROP INDEX IF EXISTS users_delete_at_idx;
+
+ALTER TABLE users
+    DROP COLUMN "deleteAt";
@@ -0,0 +1,16 @@
+ALTER TABLE users
+    ADD COLUMN "deleteAt" TIMESTAMP;
+
+CREATE INDEX users_delete_at_idx ON users ("deleteAt") WHERE "deleteAt" IS NOT NULL;
+
+ALTER TABLE users_auth_history
+    DROP CONSTRAINT "users_auth_history_userId_fkey",
+    ADD CONSTRAINT "users_auth_history_userId_fkey" FOREIGN KEY ("userId") REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE users_personal_data
+    DROP CONSTRAINT "users_personal_data_id_fkey",
+    ADD CONSTRAINT "users_personal_data_id_fkey" FOREIGN KEY (id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE users_employments
+    DROP CONSTRAINT "users_employments_userId_fkey",
+    ADD CONSTRAINT "users_employments_userId_fkey" FOREIGN KEY ("userId") REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,12 @@
+CREATE TABLE audit_events
+(
+    id          BIGSERIAL PRIMARY KEY,
+    "userId"    BIGINT       NOT NULL,
+    action      VARCHAR(64)  NOT NULL,
+    "agent"     VARCHAR(255) NOT NULL,
+    ip          INET,
+    details     JSONB,
+    "createdAt" TIMESTAMP    NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX audit_events_user_id_idx ON audit_events ("userId", "createdAt" DESC);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS users_personal_data_key_id_idx;
+DROP INDEX IF EXISTS users_personal_data_passport_id_hash_key;
+
+ALTER TABLE users_personal_data
+    DROP COLUMN "keyId",
+    DROP COLUMN "dataKey",
+    DROP COLUMN "passportIdHash",
+    ALTER COLUMN address TYPE VARCHAR(128) USING convert_from(address, 'UTF8'),
+    ALTER COLUMN "passportId" TYPE VARCHAR(128) USING convert_from("passportId", 'UTF8'),
+    ALTER COLUMN "dateOfBirth" TYPE DATE USING convert_from("dateOfBirth", 'UTF8')::DATE,
+    ALTER COLUMN "phoneNumber" TYPE CHAR(16) USING convert_from("phoneNumber", 'UTF8'),
+    ADD CONSTRAINT "users_personal_data_passportId_key" UNIQUE ("passportId");
@@ -0,0 +1,12 @@
+ALTER TABLE users_personal_data
+    DROP CONSTRAINT "users_personal_data_passportId_key",
+    ALTER COLUMN "phoneNumber" TYPE BYTEA USING convert_to(rtrim("phoneNumber"), 'UTF8'),
+    ALTER COLUMN "dateOfBirth" TYPE BYTEA USING convert_to(to_char("dateOfBirth", 'YYYY-MM-DD'), 'UTF8'),
+    ALTER COLUMN "passportId" TYPE BYTEA USING convert_to("passportId", 'UTF8'),
+    ALTER COLUMN address TYPE BYTEA USING convert_to(address, 'UTF8'),
+    ADD COLUMN "passportIdHash" BYTEA,
+    ADD COLUMN "dataKey"        BYTEA,
+    ADD COLUMN "keyId"          VARCHAR(64);
+
+CREATE UNIQUE INDEX users_personal_data_passport_id_hash_key ON users_personal_data ("passportIdHash");
+CREATE INDEX users_personal_data_key_id_idx ON users_personal_data ("keyId");
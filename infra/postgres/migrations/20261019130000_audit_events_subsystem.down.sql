@@ -0,0 +1,20 @@
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_no_update ON audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+
+DROP INDEX IF EXISTS audit_events_action_idx;
+DROP INDEX IF EXISTS audit_events_actor_id_idx;
+
+ALTER INDEX audit_events_subject_id_idx RENAME TO audit_events_user_id_idx;
+
+UPDATE audit_events SET "agent" = '' WHERE "agent" IS NULL;
+
+ALTER TABLE audit_events
+    ALTER COLUMN "agent" SET NOT NULL,
+    DROP COLUMN "after",
+    DROP COLUMN "before",
+    DROP COLUMN "requestId",
+    DROP COLUMN "actorId";
+
+ALTER TABLE audit_events
+    RENAME COLUMN "subjectId" TO "userId";
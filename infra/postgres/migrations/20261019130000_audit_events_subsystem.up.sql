@@ -0,0 +1,33 @@
+ALTER TABLE audit_events
+    RENAME COLUMN "userId" TO "subjectId";
+
+ALTER TABLE audit_events
+    ADD COLUMN "actorId"   BIGINT,
+    ADD COLUMN "requestId" VARCHAR(64),
+    ADD COLUMN "before"    JSONB,
+    ADD COLUMN "after"     JSONB,
+    ALTER COLUMN "agent" DROP NOT NULL;
+
+ALTER INDEX audit_events_user_id_idx RENAME TO audit_events_subject_id_idx;
+
+CREATE INDEX audit_events_actor_id_idx ON audit_events ("actorId", "createdAt" DESC);
+CREATE INDEX audit_events_action_idx ON audit_events (action, "createdAt" DESC);
+
+CREATE FUNCTION audit_events_append_only() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE OR DELETE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE
+    ON audit_events
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only();
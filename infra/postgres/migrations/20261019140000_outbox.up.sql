@@ -0,0 +1,17 @@
+CREATE TABLE outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    "eventId"       UUID UNIQUE NOT NULL DEFAULT gen_random_uuid(),
+    "eventType"     VARCHAR(64) NOT NULL,
+    "eventVersion"  INTEGER     NOT NULL,
+    "aggregateId"   BIGINT      NOT NULL,
+    payload         JSONB       NOT NULL,
+    "createdAt"     TIMESTAMP   NOT NULL DEFAULT current_timestamp,
+    "publishedAt"   TIMESTAMP,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    "nextAttemptAt" TIMESTAMP   NOT NULL DEFAULT current_timestamp,
+    "lastError"     TEXT,
+    "publishedTo"   VARCHAR(64)[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX outbox_pending_idx ON outbox ("nextAttemptAt", id) WHERE "publishedAt" IS NULL;
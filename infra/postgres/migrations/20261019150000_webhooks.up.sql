@@ -0,0 +1,38 @@
+CREATE TABLE webhook_subscriptions
+(
+    id          BIGSERIAL PRIMARY KEY,
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(128)  NOT NULL,
+    events      VARCHAR(64)[] NOT NULL DEFAULT '{}',
+    active      BOOLEAN       NOT NULL DEFAULT TRUE,
+    "createdAt" TIMESTAMP     NOT NULL DEFAULT current_timestamp
+);
+
+CREATE TABLE webhook_deliveries
+(
+    id               BIGSERIAL PRIMARY KEY,
+    "subscriptionId" BIGINT      NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    "eventId"        UUID        NOT NULL,
+    "eventType"      VARCHAR(64) NOT NULL,
+    attempt          INTEGER     NOT NULL,
+    "statusCode"     INTEGER,
+    error            TEXT,
+    "durationMs"     INTEGER     NOT NULL,
+    "createdAt"      TIMESTAMP   NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX webhook_deliveries_subscription_id_idx ON webhook_deliveries ("subscriptionId", "createdAt" DESC);
+
+CREATE TABLE webhook_dead_letters
+(
+    id               BIGSERIAL PRIMARY KEY,
+    "subscriptionId" BIGINT      NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    "eventId"        UUID        NOT NULL,
+    "eventType"      VARCHAR(64) NOT NULL,
+    payload          JSONB       NOT NULL,
+    attempts         INTEGER     NOT NULL,
+    "lastError"      TEXT        NOT NULL,
+    "createdAt"      TIMESTAMP   NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX webhook_dead_letters_subscription_id_idx ON webhook_dead_letters ("subscriptionId", "createdAt" DESC);
@@ -0,0 +1,12 @@
+DROP INDEX "users_employments_userId_idx";
+
+DELETE FROM users_employments e
+    USING users_employments d
+WHERE e."userId" = d."userId"
+  AND e."workplaceId" = d."workplaceId"
+  AND e.id > d.id;
+
+ALTER TABLE users_employments
+    DROP CONSTRAINT users_employments_pkey,
+    DROP COLUMN id,
+    ADD PRIMARY KEY ("userId", "workplaceId");
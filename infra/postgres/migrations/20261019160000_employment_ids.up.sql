@@ -0,0 +1,5 @@
+ALTER TABLE users_employments
+    DROP CONSTRAINT users_employments_pkey,
+    ADD COLUMN id BIGSERIAL PRIMARY KEY;
+
+CREATE INDEX "users_employments_userId_idx" ON users_employments ("userId");
@@ -0,0 +1,8 @@
+DELETE FROM countries
+WHERE code IN ('RU', 'BY', 'KZ', 'UA', 'AM', 'AZ', 'GE', 'KG', 'MD', 'TJ', 'TM', 'UZ', 'EE', 'LV', 'LT', 'FI', 'PL', 'DE', 'FR', 'GB', 'IE', 'IT', 'ES', 'PT', 'NL', 'BE', 'CH', 'AT', 'CZ', 'SK', 'HU', 'RO', 'BG', 'RS', 'GR', 'CY', 'SE', 'NO', 'DK', 'TR', 'IL', 'AE', 'SA', 'EG', 'IR', 'IN', 'CN', 'MN', 'JP', 'KR', 'VN', 'TH', 'ID', 'US', 'CA', 'MX', 'BR', 'AR', 'CU', 'AU', 'ZA')
+  AND NOT EXISTS (SELECT 1 FROM users_personal_data WHERE "liveInCountry" = countries.id);
+
+ALTER TABLE countries
+    DROP COLUMN alpha3,
+    DROP COLUMN "nameEn",
+    DROP COLUMN "phonePrefix";
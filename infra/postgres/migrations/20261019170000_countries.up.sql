@@ -0,0 +1,77 @@
+ALTER TABLE countries
+    ADD COLUMN alpha3        CHAR(3)      NOT NULL DEFAULT '',
+    ADD COLUMN "nameEn"      VARCHAR(128) NOT NULL DEFAULT '',
+    ADD COLUMN "phonePrefix" VARCHAR(8)   NOT NULL DEFAULT '';
+
+INSERT INTO countries (code, alpha3, name, "nameEn", "phonePrefix")
+VALUES
+       ('RU', 'RUS', 'Россия', 'Russia', '+7'),
+       ('BY', 'BLR', 'Беларусь', 'Belarus', '+375'),
+       ('KZ', 'KAZ', 'Казахстан', 'Kazakhstan', '+7'),
+       ('UA', 'UKR', 'Украина', 'Ukraine', '+380'),
+       ('AM', 'ARM', 'Армения', 'Armenia', '+374'),
+       ('AZ', 'AZE', 'Азербайджан', 'Azerbaijan', '+994'),
+       ('GE', 'GEO', 'Грузия', 'Georgia', '+995'),
+       ('KG', 'KGZ', 'Киргизия', 'Kyrgyzstan', '+996'),
+       ('MD', 'MDA', 'Молдова', 'Moldova', '+373'),
+       ('TJ', 'TJK', 'Таджикистан', 'Tajikistan', '+992'),
+       ('TM', 'TKM', 'Туркменистан', 'Turkmenistan', '+993'),
+       ('UZ', 'UZB', 'Узбекистан', 'Uzbekistan', '+998'),
+       ('EE', 'EST', 'Эстония', 'Estonia', '+372'),
+       ('LV', 'LVA', 'Латвия', 'Latvia', '+371'),
+       ('LT', 'LTU', 'Литва', 'Lithuania', '+370'),
+       ('FI', 'FIN', 'Финляндия', 'Finland', '+358'),
+       ('PL', 'POL', 'Польша', 'Poland', '+48'),
+       ('DE', 'DEU', 'Германия', 'Germany', '+49'),
+       ('FR', 'FRA', 'Франция', 'France', '+33'),
+       ('GB', 'GBR', 'Великобритания', 'United Kingdom', '+44'),
+       ('IE', 'IRL', 'Ирландия', 'Ireland', '+353'),
+       ('IT', 'ITA', 'Италия', 'Italy', '+39'),
+       ('ES', 'ESP', 'Испания', 'Spain', '+34'),
+       ('PT', 'PRT', 'Португалия', 'Portugal', '+351'),
+       ('NL', 'NLD', 'Нидерланды', 'Netherlands', '+31'),
+       ('BE', 'BEL', 'Бельгия', 'Belgium', '+32'),
+       ('CH', 'CHE', 'Швейцария', 'Switzerland', '+41'),
+       ('AT', 'AUT', 'Австрия', 'Austria', '+43'),
+       ('CZ', 'CZE', 'Чехия', 'Czechia', '+420'),
+       ('SK', 'SVK', 'Словакия', 'Slovakia', '+421'),
+       ('HU', 'HUN', 'Венгрия', 'Hungary', '+36'),
+       ('RO', 'ROU', 'Румыния', 'Romania', '+40'),
+       ('BG', 'BGR', 'Болгария', 'Bulgaria', '+359'),
+       ('RS', 'SRB', 'Сербия', 'Serbia', '+381'),
+       ('GR', 'GRC', 'Греция', 'Greece', '+30'),
+       ('CY', 'CYP', 'Кипр', 'Cyprus', '+357'),
+       ('SE', 'SWE', 'Швеция', 'Sweden', '+46'),
+       ('NO', 'NOR', 'Норвегия', 'Norway', '+47'),
+       ('DK', 'DNK', 'Дания', 'Denmark', '+45'),
+       ('TR', 'TUR', 'Турция', 'Turkey', '+90'),
+       ('IL', 'ISR', 'Израиль', 'Israel', '+972'),
+       ('AE', 'ARE', 'Объединённые Арабские Эмираты', 'United Arab Emirates', '+971'),
+       ('SA', 'SAU', 'Саудовская Аравия', 'Saudi Arabia', '+966'),
+       ('EG', 'EGY', 'Египет', 'Egypt', '+20'),
+       ('IR', 'IRN', 'Иран', 'Iran', '+98'),
+       ('IN', 'IND', 'Индия', 'India', '+91'),
+       ('CN', 'CHN', 'Китай', 'China', '+86'),
+       ('MN', 'MNG', 'Монголия', 'Mongolia', '+976'),
+       ('JP', 'JPN', 'Япония', 'Japan', '+81'),
+       ('KR', 'KOR', 'Республика Корея', 'South Korea', '+82'),
+       ('VN', 'VNM', 'Вьетнам', 'Vietnam', '+84'),
+       ('TH', 'THA', 'Таиланд', 'Thailand', '+66'),
+       ('ID', 'IDN', 'Индонезия', 'Indonesia', '+62'),
+       ('US', 'USA', 'США', 'United States', '+1'),
+       ('CA', 'CAN', 'Канада', 'Canada', '+1'),
+       ('MX', 'MEX', 'Мексика', 'Mexico', '+52'),
+       ('BR', 'BRA', 'Бразилия', 'Brazil', '+55'),
+       ('AR', 'ARG', 'Аргентина', 'Argentina', '+54'),
+       ('CU', 'CUB', 'Куба', 'Cuba', '+53'),
+       ('AU', 'AUS', 'Австралия', 'Australia', '+61'),
+       ('ZA', 'ZAF', 'Южно-Африканская Республика', 'South Africa', '+27')
+ON CONFLICT (code) DO UPDATE SET alpha3        = EXCLUDED.alpha3,
+                                 name          = EXCLUDED.name,
+                                 "nameEn"      = EXCLUDED."nameEn",
+                                 "phonePrefix" = EXCLUDED."phonePrefix";
+
+ALTER TABLE countries
+    ALTER COLUMN alpha3 DROP DEFAULT,
+    ALTER COLUMN "nameEn" DROP DEFAULT,
+    ALTER COLUMN "phonePrefix" DROP DEFAULT;
@@ -0,0 +1,9 @@
+CREATE TABLE scheduler_jobs
+(
+    name         VARCHAR(64)  PRIMARY KEY,
+    "startedAt"  TIMESTAMP    NOT NULL,
+    "finishedAt" TIMESTAMP    NOT NULL,
+    status       VARCHAR(16)  NOT NULL,
+    error        TEXT         NOT NULL DEFAULT '',
+    runner       VARCHAR(255) NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_not_activated_idx;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS "activatedAt";
@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS "activatedAt" TIMESTAMP;
+
+UPDATE users
+SET "activatedAt" = "createdAt"
+WHERE "activatedAt" IS NULL;
+
+CREATE INDEX IF NOT EXISTS users_not_activated_idx ON users ("createdAt", id) WHERE "activatedAt" IS NULL;
@@ -0,0 +1,21 @@
+ALTER TABLE users_auth_history
+    RENAME TO users_auth_history_partitioned;
+ALTER INDEX users_auth_history_pkey RENAME TO users_auth_history_partitioned_pkey;
+ALTER SEQUENCE users_auth_history_id_seq OWNED BY NONE;
+
+CREATE TABLE users_auth_history
+(
+    id        BIGINT PRIMARY KEY DEFAULT nextval('users_auth_history_id_seq'),
+    "userId"  BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    "agent"   VARCHAR(255) NOT NULL,
+    ip        INET         NOT NULL,
+    timestamp TIMESTAMP    NOT NULL DEFAULT current_timestamp
+);
+
+ALTER SEQUENCE users_auth_history_id_seq OWNED BY users_auth_history.id;
+
+INSERT INTO users_auth_history (id, "userId", "agent", ip, timestamp)
+SELECT id, "userId", "agent", ip, timestamp
+FROM users_auth_history_partitioned;
+
+DROP TABLE users_auth_history_partitioned;
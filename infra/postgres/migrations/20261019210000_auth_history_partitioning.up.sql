@@ -0,0 +1,42 @@
+ALTER TABLE users_auth_history
+    RENAME TO users_auth_history_legacy;
+ALTER INDEX users_auth_history_pkey RENAME TO users_auth_history_legacy_pkey;
+ALTER SEQUENCE users_auth_history_id_seq OWNED BY NONE;
+
+CREATE TABLE users_auth_history
+(
+    id        BIGINT       NOT NULL DEFAULT nextval('users_auth_history_id_seq'),
+    "userId"  BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    "agent"   VARCHAR(255) NOT NULL,
+    ip        INET         NOT NULL,
+    timestamp TIMESTAMP    NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+ALTER SEQUENCE users_auth_history_id_seq OWNED BY users_auth_history.id;
+
+CREATE INDEX users_auth_history_user_id_timestamp_idx ON users_auth_history ("userId", timestamp DESC);
+CREATE INDEX users_auth_history_timestamp_idx ON users_auth_history (timestamp);
+
+CREATE TABLE users_auth_history_default PARTITION OF users_auth_history DEFAULT;
+
+DO
+$$
+    DECLARE
+        month TIMESTAMP;
+    BEGIN
+        month := date_trunc('month', coalesce((SELECT min(timestamp) FROM users_auth_history_legacy), current_timestamp::TIMESTAMP));
+        WHILE month <= date_trunc('month', current_timestamp::TIMESTAMP + INTERVAL '1 month')
+            LOOP
+                EXECUTE format('CREATE TABLE %I PARTITION OF users_auth_history FOR VALUES FROM (%L) TO (%L)',
+                               'users_auth_history_' || to_char(month, 'YYYY_MM'), month, month + INTERVAL '1 month');
+                month := month + INTERVAL '1 month';
+            END LOOP;
+    END
+$$;
+
+INSERT INTO users_auth_history (id, "userId", "agent", ip, timestamp)
+SELECT id, "userId", "agent", ip, timestamp
+FROM users_auth_history_legacy;
+
+DROP TABLE users_auth_history_legacy;
@@ -0,0 +1,14 @@
+CREATE TABLE webhook_jobs
+(
+    id               BIGSERIAL PRIMARY KEY,
+    "subscriptionId" BIGINT    NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    "eventId"        UUID      NOT NULL,
+    event            JSONB     NOT NULL,
+    attempts         INTEGER   NOT NULL DEFAULT 0,
+    "nextAttemptAt"  TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    "completedAt"    TIMESTAMP,
+    "createdAt"      TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    UNIQUE ("subscriptionId", "eventId")
+);
+
+CREATE INDEX webhook_jobs_pending_idx ON webhook_jobs ("nextAttemptAt", id) WHERE "completedAt" IS NULL;
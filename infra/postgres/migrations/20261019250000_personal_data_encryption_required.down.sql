@@ -0,0 +1,4 @@
+ALTER TABLE users_personal_data
+    ALTER COLUMN "keyId" DROP NOT NULL,
+    ALTER COLUMN "dataKey" DROP NOT NULL,
+    ALTER COLUMN "passportIdHash" DROP NOT NULL;
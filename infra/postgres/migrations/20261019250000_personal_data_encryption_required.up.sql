@@ -0,0 +1,11 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM users_personal_data WHERE "keyId" IS NULL) THEN
+        RAISE EXCEPTION 'в users_personal_data остались незашифрованные строки, выполните rotate-keys -legacy';
+    END IF;
+END $$;
+
+ALTER TABLE users_personal_data
+    ALTER COLUMN "passportIdHash" SET NOT NULL,
+    ALTER COLUMN "dataKey" SET NOT NULL,
+    ALTER COLUMN "keyId" SET NOT NULL;
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+	"x-bank-users/core/outbox"
+	"x-bank-users/entity"
+)
+
+func (s *Service) addOutboxEvent(ctx context.Context, aggregateId int64, payload entity.EventPayload) error {
+	const query = `
+INSERT INTO outbox ("eventType", "eventVersion", "aggregateId", payload)
+VALUES (@eventType, @eventVersion, @aggregateId, @payload)`
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	_, err = s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"eventType":    payload.EventType(),
+		"eventVersion": payload.EventVersion(),
+		"aggregateId":  aggregateId,
+		"payload":      data,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) ClaimOutboxEvents(ctx context.Context, limit int, lease time.Duration) ([]outbox.Record, error) {
+	const query = `
+UPDATE outbox
+SET "nextAttemptAt" = @leaseUntil
+WHERE id IN (SELECT id
+             FROM outbox
+             WHERE "publishedAt" IS NULL AND "nextAttemptAt" <= @now
+             ORDER BY id
+             LIMIT @limit FOR UPDATE SKIP LOCKED)
+RETURNING id, attempts, "publishedTo", "eventId", "eventType", "eventVersion", "aggregateId", "createdAt", payload`
+
+	now := time.Now()
+	rows, err := s.querier(ctx).QueryContext(ctx, query, pgx.NamedArgs{
+		"now":        now,
+		"leaseUntil": now.Add(lease),
+		"limit":      limit,
+	},
+	)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+
+	var records []outbox.Record
+	for rows.Next() {
+		var record outbox.Record
+		var payload []byte
+		err = rows.Scan(&record.Id, &record.Attempts, typeMap.SQLScanner(&record.PublishedTo), &record.Event.Id, &record.Event.Type, &record.Event.Version,
+			&record.Event.AggregateId, &record.Event.OccurredAt, &payload)
+		if err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		record.Event.Data = payload
+		records = append(records, record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return records, nil
+}
+
+func (s *Service) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	const query = `UPDATE outbox SET "publishedAt" = current_timestamp, attempts = attempts + 1, "lastError" = NULL WHERE id = @id`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id": id,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) MarkOutboxEventFailed(ctx context.Context, id int64, nextAttemptAt time.Time, reason string, publishedTo []string) error {
+	const query = `
+UPDATE outbox
+SET attempts = attempts + 1, "nextAttemptAt" = @nextAttemptAt, "lastError" = @lastError, "publishedTo" = @publishedTo
+WHERE id = @id`
+
+	if publishedTo == nil {
+		publishedTo = []string{}
+	}
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id":            id,
+		"nextAttemptAt": nextAttemptAt,
+		"lastError":     reason,
+		"publishedTo":   publishedTo,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"x-bank-users/core/scheduler"
+)
+
+const (
+	schedulerLockClass = 4801
+)
+
+func (s *Service) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, false, s.wrapQueryError(err)
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`, schedulerLockClass, name).Scan(&acquired)
+	if err != nil || !acquired {
+		_ = conn.Close()
+		if err != nil {
+			return nil, false, s.wrapQueryError(err)
+		}
+		return nil, false, nil
+	}
+
+	release := func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1, hashtext($2))`, schedulerLockClass, name)
+		_ = conn.Close()
+	}
+
+	return release, true, nil
+}
+
+func (s *Service) SaveJobRun(ctx context.Context, run scheduler.JobRun) error {
+	const query = `
+INSERT INTO scheduler_jobs (name, "startedAt", "finishedAt", status, error, runner)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (name) DO UPDATE SET "startedAt"  = EXCLUDED."startedAt",
+                                 "finishedAt" = EXCLUDED."finishedAt",
+                                 status       = EXCLUDED.status,
+                                 error        = EXCLUDED.error,
+                                 runner       = EXCLUDED.runner`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, run.Name, run.StartedAt, run.FinishedAt, run.Status, run.Error, run.Runner)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetJobRun(ctx context.Context, name string) (scheduler.JobRun, error) {
+	const query = `SELECT name, "startedAt", "finishedAt", status, error, runner FROM scheduler_jobs WHERE name = $1`
+
+	row := s.querier(ctx).QueryRowContext(ctx, query, name)
+	if err := row.Err(); err != nil {
+		return scheduler.JobRun{}, s.wrapQueryError(err)
+	}
+
+	var run scheduler.JobRun
+	if err := row.Scan(&run.Name, &run.StartedAt, &run.FinishedAt, &run.Status, &run.Error, &run.Runner); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return scheduler.JobRun{}, nil
+		}
+		return scheduler.JobRun{}, s.wrapScanError(err)
+	}
+
+	return run, nil
+}
+
+func (s *Service) GetJobRuns(ctx context.Context) ([]scheduler.JobRun, error) {
+	const query = `SELECT name, "startedAt", "finishedAt", status, error, runner FROM scheduler_jobs ORDER BY name`
+
+	rows, err := s.querier(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []scheduler.JobRun
+	for rows.Next() {
+		var run scheduler.JobRun
+		if err = rows.Scan(&run.Name, &run.StartedAt, &run.FinishedAt, &run.Status, &run.Error, &run.Runner); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		runs = append(runs, run)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return runs, nil
+}
@@ -7,30 +7,55 @@ import (
 	"fmt"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5/stdlib"
+	"io/fs"
 	"time"
 	"x-bank-users/cerrors"
 	"x-bank-users/core/web"
 	"x-bank-users/entity"
 	"x-bank-users/ercodes"
+	"x-bank-users/infra/postgres/migrations"
 )
 
 const (
-	uniqueLoginConstraint = `users_login_key`
-	uniqueEmailConstraint = `users_email_key`
+	uniqueLoginConstraint      = `users_login_key`
+	uniqueEmailConstraint      = `users_email_key`
+	uniquePassportIdConstraint = `users_personal_data_passport_id_hash_key`
 )
 
 type (
+	FieldEncryptor interface {
+		CurrentKeyId() string
+		NewDataKey(ctx context.Context) ([]byte, string, []byte, error)
+		UnwrapDataKey(ctx context.Context, keyId string, wrappedKey []byte) ([]byte, error)
+		Encrypt(dataKey []byte, plaintext string, aad []byte) ([]byte, error)
+		Decrypt(dataKey []byte, ciphertext []byte, aad []byte) (string, error)
+		BlindIndex(value string) []byte
+	}
+
 	Service struct {
-		db *sql.DB
+		db         *sql.DB
+		encryptor  FieldEncryptor
+		migrations fs.FS
+
+		txIsolation  sql.IsolationLevel
+		txMaxRetries int
 	}
 )
 
-func NewService(login, password, host string, port int, database string, maxCons int) (Service, error) {
-	db, err := sql.Open("pgx", fmt.Sprintf("postgres://%s:%s@%s:%d/%s", login, password, host, port, database))
+func NewService(login, password, host string, port int, database string, maxCons int, isolationLevel string, txMaxRetries int, encryptor FieldEncryptor) (Service, error) {
+	txIsolation, err := parseIsolationLevel(isolationLevel)
+	if err != nil {
+		return Service{}, err
+	}
+
+	connConfig, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s:%d/%s", login, password, host, port, database))
 	if err != nil {
 		return Service{}, err
 	}
+	connConfig.Tracer = queryTracer{}
+
+	db := stdlib.OpenDB(*connConfig)
 
 	db.SetMaxOpenConns(maxCons)
 
@@ -39,37 +64,52 @@ func NewService(login, password, host string, port int, database string, maxCons
 	}
 
 	return Service{
-		db: db,
+		db:           db,
+		encryptor:    encryptor,
+		migrations:   migrations.FS,
+		txIsolation:  txIsolation,
+		txMaxRetries: txMaxRetries,
 	}, err
 }
 
 func (s *Service) CreateUser(ctx context.Context, login, email string, passwordHash []byte) (int64, error) {
-	const query = `INSERT INTO users (login, email, password) VALUES (@login, @email, @password) RETURNING id`
-
-	row := s.db.QueryRowContext(ctx, query,
-		pgx.NamedArgs{
-			"login":    login,
-			"email":    email,
-			"password": passwordHash,
-		},
-	)
+	const query = `INSERT INTO users (login, email, password, "activatedAt") VALUES (@login, @email, @password, NULL) RETURNING id`
 
-	if err := row.Err(); err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			switch pgErr.ConstraintName {
-			case uniqueLoginConstraint:
-				return 0, cerrors.NewErrorWithUserMessage(ercodes.LoginAlreadyTaken, nil, "Логин уже занят")
-			case uniqueEmailConstraint:
-				return 0, cerrors.NewErrorWithUserMessage(ercodes.EmailAlreadyTaken, nil, "Емейл уже занят")
+	var userId int64
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		row := s.querier(ctx).QueryRowContext(ctx, query,
+			pgx.NamedArgs{
+				"login":    login,
+				"email":    email,
+				"password": passwordHash,
+			},
+		)
+
+		if err := row.Err(); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.ConstraintName {
+				case uniqueLoginConstraint:
+					return cerrors.NewErrorWithUserMessage(ercodes.LoginAlreadyTaken, nil, "Логин уже занят").WithKind(cerrors.KindConflict)
+				case uniqueEmailConstraint:
+					return cerrors.NewErrorWithUserMessage(ercodes.EmailAlreadyTaken, nil, "Емейл уже занят").WithKind(cerrors.KindConflict)
+				}
 			}
+			return s.wrapQueryError(err)
 		}
-		return 0, s.wrapQueryError(err)
-	}
 
-	var userId int64
-	if err := row.Scan(&userId); err != nil {
-		return 0, s.wrapScanError(err)
+		if err := row.Scan(&userId); err != nil {
+			return s.wrapScanError(err)
+		}
+
+		return s.addOutboxEvent(ctx, userId, entity.UserSignedUpEvent{
+			UserId: userId,
+			Login:  login,
+			Email:  email,
+		})
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	return userId, nil
@@ -78,12 +118,12 @@ func (s *Service) CreateUser(ctx context.Context, login, email string, passwordH
 func (s *Service) GetSignInDataByLogin(ctx context.Context, login string) (web.UserDataToSignIn, error) {
 	var userData web.UserDataToSignIn
 
-	const query = `SELECT users.id, users.password, users."telegramId", users_personal_data.id IS NOT NULL as "hasPersonalData"
+	const query = `SELECT users.id, users.password, users."telegramId", users_personal_data.id IS NOT NULL as "hasPersonalData", users."deleteAt"
 				   FROM users
 				   LEFT JOIN users_personal_data USING (id) 
 				   WHERE users.login = @login`
 
-	row := s.db.QueryRowContext(ctx, query,
+	row := s.querier(ctx).QueryRowContext(ctx, query,
 		pgx.NamedArgs{
 			"login": login,
 		},
@@ -93,9 +133,9 @@ func (s *Service) GetSignInDataByLogin(ctx context.Context, login string) (web.U
 		return web.UserDataToSignIn{}, s.wrapQueryError(err)
 	}
 
-	if err := row.Scan(&userData.Id, &userData.PasswordHash, &userData.TelegramId, &userData.HasPersonalData); err != nil {
+	if err := row.Scan(&userData.Id, &userData.PasswordHash, &userData.TelegramId, &userData.HasPersonalData, &userData.DeleteAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return web.UserDataToSignIn{}, cerrors.NewErrorWithUserMessage(ercodes.InvalidLoginOrPassword, err, "Неверный логин пароль")
+			return web.UserDataToSignIn{}, cerrors.NewErrorWithUserMessage(ercodes.InvalidLoginOrPassword, err, "Неверный логин пароль").WithKind(cerrors.KindUnauthenticated)
 		}
 		return web.UserDataToSignIn{}, s.wrapScanError(err)
 	}
@@ -106,17 +146,17 @@ func (s *Service) GetSignInDataByLogin(ctx context.Context, login string) (web.U
 func (s *Service) GetSignInDataById(ctx context.Context, id int64) (web.UserDataToSignIn, error) {
 	var userData web.UserDataToSignIn
 
-	const query = `SELECT users.id, users.password, users."telegramId", users_personal_data.id IS NOT NULL as "hasUsersPersonalData" FROM users LEFT JOIN users_personal_data USING (id) WHERE id = @id`
+	const query = `SELECT users.id, users.password, users."telegramId", users_personal_data.id IS NOT NULL as "hasUsersPersonalData", users."deleteAt" FROM users LEFT JOIN users_personal_data USING (id) WHERE id = @id`
 
-	row := s.db.QueryRowContext(ctx, query,
+	row := s.querier(ctx).QueryRowContext(ctx, query,
 		pgx.NamedArgs{
 			"id": id,
 		},
 	)
 
-	if err := row.Scan(&userData.Id, &userData.PasswordHash, &userData.TelegramId, &userData.HasPersonalData); err != nil {
+	if err := row.Scan(&userData.Id, &userData.PasswordHash, &userData.TelegramId, &userData.HasPersonalData, &userData.DeleteAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return web.UserDataToSignIn{}, s.wrapQueryError(err)
+			return web.UserDataToSignIn{}, s.wrapUserNotFoundError(err)
 		}
 		return web.UserDataToSignIn{}, s.wrapScanError(err)
 	}
@@ -127,7 +167,7 @@ func (s *Service) GetSignInDataById(ctx context.Context, id int64) (web.UserData
 func (s *Service) UserIdByLoginAndEmail(ctx context.Context, login, email string) (int64, error) {
 	const query = `SELECT id FROM users WHERE login = @login AND email = @email`
 
-	row := s.db.QueryRowContext(ctx, query, pgx.NamedArgs{
+	row := s.querier(ctx).QueryRowContext(ctx, query, pgx.NamedArgs{
 		"login": login,
 		"email": email,
 	},
@@ -142,6 +182,9 @@ func (s *Service) UserIdByLoginAndEmail(ctx context.Context, login, email string
 	var userId int64
 	err = row.Scan(&userId)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, s.wrapUserNotFoundError(err)
+		}
 		return 0, s.wrapScanError(err)
 	}
 	return userId, nil
@@ -150,47 +193,59 @@ func (s *Service) UserIdByLoginAndEmail(ctx context.Context, login, email string
 func (s *Service) UpdatePassword(ctx context.Context, id int64, passwordHash []byte) error {
 	const query = `UPDATE users SET password = @password WHERE id = @id`
 
-	_, err := s.db.ExecContext(ctx, query, pgx.NamedArgs{
-		"id":       id,
-		"password": passwordHash,
-	},
-	)
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+			"id":       id,
+			"password": passwordHash,
+		},
+		)
 
-	if err != nil {
-		return s.wrapQueryError(err)
-	}
+		if err != nil {
+			return s.wrapQueryError(err)
+		}
 
-	return nil
+		return s.addOutboxEvent(ctx, id, entity.UserPasswordChangedEvent{UserId: id})
+	})
 }
 
 func (s *Service) UpdateTelegramId(ctx context.Context, telegramId *int64, userId int64) error {
 	const query = `UPDATE users SET "telegramId" = @telegramId WHERE id = @id`
 
-	_, err := s.db.ExecContext(ctx, query, pgx.NamedArgs{
-		"id":         userId,
-		"telegramId": telegramId,
-	},
-	)
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+			"id":         userId,
+			"telegramId": telegramId,
+		},
+		)
 
-	if err != nil {
-		return s.wrapQueryError(err)
-	}
+		if err != nil {
+			return s.wrapQueryError(err)
+		}
 
-	return nil
+		if telegramId == nil {
+			return s.addOutboxEvent(ctx, userId, entity.UserTelegramUnboundEvent{
+				UserId: userId,
+			})
+		}
+		return s.addOutboxEvent(ctx, userId, entity.UserTelegramBoundEvent{
+			UserId:     userId,
+			TelegramId: *telegramId,
+		})
+	})
 }
 
 func (s *Service) GetUserPersonalDataById(ctx context.Context, userId int64) (*web.UserPersonalData, error) {
-	const query = `SELECT "phoneNumber", "firstName", "lastName", "fathersName", "dateOfBirth", "passportId", "address", gender, countries.name FROM users_personal_data JOIN countries on users_personal_data."liveInCountry" = countries.id where users_personal_data."id" = $1`
+	const query = `SELECT "phoneNumber", "firstName", "lastName", "fathersName", "dateOfBirth", "passportId", "address", gender, countries.name, users_personal_data."liveInCountry", "dataKey", "keyId" FROM users_personal_data JOIN countries on users_personal_data."liveInCountry" = countries.id where users_personal_data."id" = $1`
 
-	row := s.db.QueryRowContext(ctx, query, userId)
+	row := s.querier(ctx).QueryRowContext(ctx, query, userId)
 
 	if err := row.Err(); err != nil {
 		return nil, s.wrapQueryError(err)
 	}
-	row = s.db.QueryRowContext(ctx, query, userId)
 
 	var userPersonalData web.UserPersonalData
-	err := row.Scan(&userPersonalData.PhoneNumber, &userPersonalData.FirstName, &userPersonalData.LastName, &userPersonalData.FathersName, &userPersonalData.DateOfBirth, &userPersonalData.PassportId, &userPersonalData.Address, &userPersonalData.Gender, &userPersonalData.LiveInCountry)
+	var fields encryptedPersonalData
+	err := row.Scan(&fields.PhoneNumber, &userPersonalData.FirstName, &userPersonalData.LastName, &userPersonalData.FathersName, &fields.DateOfBirth, &fields.PassportId, &fields.Address, &userPersonalData.Gender, &userPersonalData.LiveInCountry, &userPersonalData.LiveInCountryId, &fields.DataKey, &fields.KeyId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -198,12 +253,155 @@ func (s *Service) GetUserPersonalDataById(ctx context.Context, userId int64) (*w
 		return nil, s.wrapScanError(err)
 	}
 
+	plain, err := s.decryptPersonalData(ctx, userId, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	userPersonalData.PhoneNumber = plain.PhoneNumber
+	userPersonalData.PassportId = plain.PassportId
+	userPersonalData.Address = plain.Address
+	userPersonalData.DateOfBirth, err = time.Parse(time.DateOnly, plain.DateOfBirth)
+	if err != nil {
+		return nil, s.wrapScanError(err)
+	}
+
 	return &userPersonalData, nil
 }
 
-func (s *Service) DeleteUsersWithExpiredActivation(ctx context.Context, expirationTime time.Duration) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM users WHERE "createdAt" < $1`, time.Now().Add(-expirationTime))
+func (s *Service) DeleteUsersWithExpiredActivation(ctx context.Context, expirationTime time.Duration, batchSize int, dryRun bool) (int64, error) {
+	createdBefore := time.Now().Add(-expirationTime)
+
+	if dryRun {
+		const query = `SELECT count(*) FROM users WHERE "activatedAt" IS NULL AND "createdAt" < $1`
+
+		var count int64
+		if err := s.querier(ctx).QueryRowContext(ctx, query, createdBefore).Scan(&count); err != nil {
+			return 0, s.wrapScanError(err)
+		}
+		return count, nil
+	}
+
+	const query = `
+SELECT id FROM users
+WHERE "activatedAt" IS NULL AND "createdAt" < $1 AND id > $2
+ORDER BY id
+LIMIT $3 FOR UPDATE SKIP LOCKED`
+
+	var deleted, lastId int64
+	for {
+		var userIds []int64
+		err := s.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			userIds, err = s.selectUserIds(ctx, query, createdBefore, lastId, batchSize)
+			if err != nil || len(userIds) == 0 {
+				return err
+			}
+
+			return s.deleteUsers(ctx, userIds)
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		deleted += int64(len(userIds))
+		if len(userIds) < batchSize {
+			return deleted, nil
+		}
+		lastId = userIds[len(userIds)-1]
+	}
+}
+
+func (s *Service) selectUserIds(ctx context.Context, query string, args ...any) ([]int64, error) {
+	rows, err := s.querier(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var userIds []int64
+	for rows.Next() {
+		var userId int64
+		if err = rows.Scan(&userId); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		userIds = append(userIds, userId)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return userIds, nil
+}
+
+func (s *Service) deleteUsers(ctx context.Context, userIds []int64) error {
+	queries := []string{
+		`DELETE FROM users_auth_history WHERE "userId" = ANY($1)`,
+		`DELETE FROM users_employments WHERE "userId" = ANY($1)`,
+		`DELETE FROM users_personal_data WHERE id = ANY($1)`,
+		`DELETE FROM users WHERE id = ANY($1)`,
+	}
+	for _, query := range queries {
+		if _, err := s.querier(ctx).ExecContext(ctx, query, userIds); err != nil {
+			return s.wrapQueryError(err)
+		}
+	}
+
+	for _, userId := range userIds {
+		if err := s.addOutboxEvent(ctx, userId, entity.UserDeletedEvent{UserId: userId}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ScheduleUserDeletion(ctx context.Context, userId int64, deleteAt time.Time) error {
+	const query = `UPDATE users SET "deleteAt" = @deleteAt WHERE id = @id`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id":       userId,
+		"deleteAt": deleteAt,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) ActivateUser(ctx context.Context, userId int64) error {
+	const query = `UPDATE users SET "activatedAt" = now() WHERE id = $1 AND "activatedAt" IS NULL`
+
+	if _, err := s.querier(ctx).ExecContext(ctx, query, userId); err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) LockUserById(ctx context.Context, userId int64) error {
+	const query = `SELECT id FROM users WHERE id = $1 FOR UPDATE`
+
+	var id int64
+	if err := s.querier(ctx).QueryRowContext(ctx, query, userId).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.wrapUserNotFoundError(err)
+		}
+		return s.wrapScanError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) CancelUserDeletion(ctx context.Context, userId int64) error {
+	const query = `UPDATE users SET "deleteAt" = NULL WHERE id = @id`
 
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id": userId,
+	},
+	)
 	if err != nil {
 		return s.wrapQueryError(err)
 	}
@@ -211,10 +409,53 @@ func (s *Service) DeleteUsersWithExpiredActivation(ctx context.Context, expirati
 	return nil
 }
 
+func (s *Service) DeleteUsersScheduledForDeletion(ctx context.Context, batchSize int, dryRun bool) (int64, error) {
+	deleteBefore := time.Now()
+
+	if dryRun {
+		const query = `SELECT count(*) FROM users WHERE "deleteAt" <= $1`
+
+		var count int64
+		if err := s.querier(ctx).QueryRowContext(ctx, query, deleteBefore).Scan(&count); err != nil {
+			return 0, s.wrapScanError(err)
+		}
+		return count, nil
+	}
+
+	const query = `
+SELECT id FROM users
+WHERE "deleteAt" <= $1 AND id > $2
+ORDER BY id
+LIMIT $3 FOR UPDATE SKIP LOCKED`
+
+	var deleted, lastId int64
+	for {
+		var userIds []int64
+		err := s.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			userIds, err = s.selectUserIds(ctx, query, deleteBefore, lastId, batchSize)
+			if err != nil || len(userIds) == 0 {
+				return err
+			}
+
+			return s.deleteUsers(ctx, userIds)
+		})
+		if err != nil {
+			return deleted, err
+		}
+
+		deleted += int64(len(userIds))
+		if len(userIds) < batchSize {
+			return deleted, nil
+		}
+		lastId = userIds[len(userIds)-1]
+	}
+}
+
 func (s *Service) GetUserDataById(ctx context.Context, id int64) (web.UserData, error) {
 	const query = `SELECT id, uuid, login, email, "telegramId", "createdAt" FROM users WHERE id = @id`
 
-	row := s.db.QueryRowContext(ctx, query, pgx.NamedArgs{
+	row := s.querier(ctx).QueryRowContext(ctx, query, pgx.NamedArgs{
 		"id": id,
 	},
 	)
@@ -226,6 +467,9 @@ func (s *Service) GetUserDataById(ctx context.Context, id int64) (web.UserData,
 	var userData web.UserData
 	err := row.Scan(&userData.Id, &userData.UUID, &userData.Login, &userData.Email, &userData.TelegramId, &userData.CreatedAt)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return web.UserData{}, s.wrapUserNotFoundError(err)
+		}
 		return web.UserData{}, s.wrapScanError(err)
 	}
 
@@ -235,7 +479,7 @@ func (s *Service) GetUserDataById(ctx context.Context, id int64) (web.UserData,
 func (s *Service) AddUsersAuthHistory(ctx context.Context, userId int64, agent, ip string) error {
 	const query = `INSERT INTO users_auth_history ("userId", "agent", ip) VALUES (@userId, @agent, @ip)`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := s.querier(ctx).ExecContext(ctx, query,
 		pgx.NamedArgs{
 			"userId": userId,
 			"agent":  agent,
@@ -252,11 +496,12 @@ func (s *Service) AddUsersAuthHistory(ctx context.Context, userId int64, agent,
 func (s *Service) GetUserAuthHistory(ctx context.Context, userId int64) ([]web.UserAuthHistoryData, error) {
 	const query = `SELECT "userId", "agent", "ip", "timestamp" FROM users_auth_history WHERE "userId" = $1 ORDER BY timestamp DESC `
 
-	rows, err := s.db.QueryContext(ctx, query, userId)
+	rows, err := s.querier(ctx).QueryContext(ctx, query, userId)
 
 	if err != nil {
 		return nil, s.wrapQueryError(err)
 	}
+	defer func() { _ = rows.Close() }()
 
 	var userAuthHistoryData []web.UserAuthHistoryData
 	for rows.Next() {
@@ -266,27 +511,40 @@ func (s *Service) GetUserAuthHistory(ctx context.Context, userId int64) ([]web.U
 		}
 		userAuthHistoryData = append(userAuthHistoryData, userAuthHist)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
 
 	return userAuthHistoryData, nil
 }
 
-func (s *Service) AddUserPersonalDataById(_ context.Context, userId int64, data entity.UserPersonalData) error {
+func (s *Service) AddUserPersonalDataById(ctx context.Context, userId int64, data entity.UserPersonalData) error {
 	const query = `
 INSERT INTO users_personal_data 
-    (id, "phoneNumber", "firstName", "lastName", "fathersName", "dateOfBirth", "passportId", address, gender, "liveInCountry") 
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
-
-	_, err := s.db.Exec(query, userId, data.PhoneNumber, data.FirstName, data.LastName, data.FathersName, data.DateOfBirth,
-		data.PassportId, data.Address, data.Gender, data.LiveInCountryId)
+    (id, "phoneNumber", "firstName", "lastName", "fathersName", "dateOfBirth", "passportId", address, gender, "liveInCountry", "passportIdHash", "dataKey", "keyId") 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 
+	fields, err := s.encryptPersonalData(ctx, userId, data)
 	if err != nil {
-		return s.wrapQueryError(err)
+		return err
 	}
 
-	return nil
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.querier(ctx).ExecContext(ctx, query, userId, fields.PhoneNumber, data.FirstName, data.LastName, data.FathersName, fields.DateOfBirth,
+			fields.PassportId, fields.Address, data.Gender, data.LiveInCountryId, fields.PassportIdHash, fields.DataKey, fields.KeyId)
+
+		if err != nil {
+			return s.wrapPersonalDataError(err)
+		}
+
+		return s.addOutboxEvent(ctx, userId, entity.UserPersonalDataFilledEvent{
+			UserId:          userId,
+			LiveInCountryId: data.LiveInCountryId,
+		})
+	})
 }
 
-func (s *Service) UpdateUserPersonalDataById(_ context.Context, userId int64, data entity.UserPersonalData) error {
+func (s *Service) UpdateUserPersonalDataById(ctx context.Context, userId int64, data entity.UserPersonalData) error {
 	const query = `
 UPDATE users_personal_data 
 SET "phoneNumber" = $1, 
@@ -297,14 +555,21 @@ SET "phoneNumber" = $1,
     "passportId" = $6, 
     address = $7, 
     gender = $8, 
-    "liveInCountry" = $9
-WHERE id = $10`
+    "liveInCountry" = $9,
+    "passportIdHash" = $10,
+    "dataKey" = $11,
+    "keyId" = $12
+WHERE id = $13`
 
-	_, err := s.db.Exec(query, data.PhoneNumber, data.FirstName, data.LastName, data.FathersName, data.DateOfBirth,
-		data.PassportId, data.Address, data.Gender, data.LiveInCountryId, userId)
+	fields, err := s.encryptPersonalData(ctx, userId, data)
+	if err != nil {
+		return err
+	}
 
+	_, err = s.querier(ctx).ExecContext(ctx, query, fields.PhoneNumber, data.FirstName, data.LastName, data.FathersName, fields.DateOfBirth,
+		fields.PassportId, fields.Address, data.Gender, data.LiveInCountryId, fields.PassportIdHash, fields.DataKey, fields.KeyId, userId)
 	if err != nil {
-		return s.wrapQueryError(err)
+		return s.wrapPersonalDataError(err)
 	}
 
 	return nil
@@ -312,24 +577,25 @@ WHERE id = $10`
 
 func (s *Service) GetUserWorkplaces(ctx context.Context, userId int64) ([]entity.UserWorkplace, error) {
 	const query = `
-SELECT w.name, w.address, e.position, e."startDate", e."endDate"
+SELECT e.id, w.name, w.address, e.position, e."startDate", e."endDate"
 FROM users_employments e
 JOIN workplaces w ON e."workplaceId" = w.id
 WHERE "userId" = $1 
 ORDER BY e."startDate" DESC `
 
-	rows, err := s.db.QueryContext(ctx, query, userId)
+	rows, err := s.querier(ctx).QueryContext(ctx, query, userId)
 
 	if err != nil {
 		return nil, s.wrapQueryError(err)
 	}
+	defer func() { _ = rows.Close() }()
 
 	var wps []entity.UserWorkplace
 	for rows.Next() {
 		var wp entity.UserWorkplace
 		var start time.Time
 		var end *time.Time
-		if err = rows.Scan(&wp.CompanyName, &wp.CompanyAddress, &wp.Position, &start, &end); err != nil {
+		if err = rows.Scan(&wp.Id, &wp.CompanyName, &wp.CompanyAddress, &wp.Position, &start, &end); err != nil {
 			return nil, s.wrapScanError(err)
 		}
 		wp.StartDate = start.Unix()
@@ -339,33 +605,100 @@ ORDER BY e."startDate" DESC `
 		}
 		wps = append(wps, wp)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
 
 	return wps, nil
 }
 
-func (s *Service) AddUserWorkplace(ctx context.Context, userId int64, work entity.Workplace) error {
-	const queryWork = `
-SELECT id FROM workplaces WHERE name = $1
-`
-	var workId int64
-	err := s.db.QueryRowContext(ctx, queryWork, work.CompanyName).Scan(&workId)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return s.wrapQueryError(err)
-	}
+func (s *Service) AddUserWorkplace(ctx context.Context, userId int64, work entity.Workplace) (int64, error) {
+	const query = `
+INSERT INTO users_employments ("userId", "workplaceId", position, "startDate", "endDate")
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id`
 
-	const queryAddWork = `INSERT INTO workplaces (name, address) VALUES ($1, $2)`
-	if workId == 0 {
-		_, err = s.db.Exec(queryAddWork, work.CompanyName, work.CompanyAddress)
+	var id int64
+	err := s.WithinTx(ctx, func(ctx context.Context) error {
+		workId, err := s.upsertWorkplace(ctx, work)
 		if err != nil {
 			return err
 		}
-	}
 
-	const query = `INSERT INTO users_employments ("userId", "workplaceId", position, "startDate", "endDate") VALUES ($1, $2, $3, $4, $5)`
+		err = s.querier(ctx).QueryRowContext(ctx, query, userId, workId, work.Position, work.StartDate, work.EndDate).Scan(&id)
+		if err != nil {
+			return s.wrapQueryError(err)
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+func (s *Service) UpdateUserWorkplace(ctx context.Context, userId, id int64, work entity.Workplace) error {
+	const query = `
+UPDATE users_employments
+SET "workplaceId" = $1, position = $2, "startDate" = $3, "endDate" = $4
+WHERE id = $5 AND "userId" = $6`
+
+	return s.WithinTx(ctx, func(ctx context.Context) error {
+		workId, err := s.upsertWorkplace(ctx, work)
+		if err != nil {
+			return err
+		}
+
+		res, err := s.querier(ctx).ExecContext(ctx, query, workId, work.Position, work.StartDate, work.EndDate, id, userId)
+		if err != nil {
+			return s.wrapQueryError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return s.wrapQueryError(err)
+		}
+		if affected == 0 {
+			return cerrors.NewErrorWithUserMessage(ercodes.WorkplaceNotFound, nil, "Место работы не найдено").WithKind(cerrors.KindNotFound)
+		}
+
+		return nil
+	})
+}
+
+func (s *Service) DeleteUserWorkplace(ctx context.Context, userId, id int64) error {
+	const query = `DELETE FROM users_employments WHERE id = $1 AND "userId" = $2`
 
-	_, err = s.db.Exec(query, userId, workId, work.Position, work.StartDate, work.EndDate)
+	res, err := s.querier(ctx).ExecContext(ctx, query, id, userId)
 	if err != nil {
 		return s.wrapQueryError(err)
 	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+	if affected == 0 {
+		return cerrors.NewErrorWithUserMessage(ercodes.WorkplaceNotFound, nil, "Место работы не найдено").WithKind(cerrors.KindNotFound)
+	}
+
 	return nil
 }
+
+func (s *Service) upsertWorkplace(ctx context.Context, work entity.Workplace) (int64, error) {
+	const query = `
+INSERT INTO workplaces (name, address)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, address`
+
+	var id int64
+	var address string
+	if err := s.querier(ctx).QueryRowContext(ctx, query, work.CompanyName, work.CompanyAddress).Scan(&id, &address); err != nil {
+		return 0, s.wrapQueryError(err)
+	}
+	if address != work.CompanyAddress {
+		return 0, cerrors.NewErrorWithUserMessage(ercodes.WorkplaceAddressMismatch, nil, "Компания уже зарегистрирована с другим адресом").WithKind(cerrors.KindConflict)
+	}
+
+	return id, nil
+}
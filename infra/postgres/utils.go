@@ -1,18 +1,139 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"log/slog"
+	"net"
+	"strings"
 	"x-bank-users/cerrors"
 	"x-bank-users/ercodes"
+	"x-bank-users/logging"
 )
 
+type (
+	querier interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	}
+
+	txCtxKey struct{}
+)
+
+const (
+	serializationFailureCode   = "40001"
+	deadlockDetectedCode       = "40P01"
+	adminShutdownCode          = "57P01"
+	connectionExceptionClass   = "08"
+	insufficientResourcesClass = "53"
+)
+
+func (s *Service) querier(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txCtxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return s.db
+}
+
+func (s *Service) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *Service) Stats() sql.DBStats {
+	return s.db.Stats()
+}
+
+func (s *Service) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txCtxKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 0; attempt <= s.txMaxRetries; attempt++ {
+		if err = s.runTx(ctx, fn); !isSerializationFailure(err) {
+			return err
+		}
+		logging.FromContext(ctx).Debug("transaction serialization failure, retrying", slog.Int("attempt", attempt+1))
+	}
+
+	return err
+}
+
+func (s *Service) runTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: s.txIsolation})
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = fn(context.WithValue(ctx, txCtxKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == serializationFailureCode
+}
+
+func isTransientError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailureCode ||
+			pgErr.Code == deadlockDetectedCode ||
+			strings.HasPrefix(pgErr.Code, connectionExceptionClass) ||
+			strings.HasPrefix(pgErr.Code, insufficientResourcesClass) ||
+			pgErr.Code == adminShutdownCode
+	}
+
+	var netErr net.Error
+	return pgconn.SafeToRetry(err) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr)
+}
+
+func parseIsolationLevel(level string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(level) {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	}
+
+	return sql.LevelDefault, errors.New("неизвестный уровень изоляции " + level)
+}
+
 func (s *Service) Close() {
 	_ = s.db.Close()
 }
 
 func (s *Service) wrapQueryError(err error) error {
-	return cerrors.NewErrorWithUserMessage(ercodes.PostgresQuery, err, "Ошибка работы с базой данных")
+	cErr := cerrors.NewErrorWithUserMessage(ercodes.PostgresQuery, err, "Ошибка работы с базой данных")
+	if isTransientError(err) {
+		return cErr.WithKind(cerrors.KindUnavailable).WithRetryable(true)
+	}
+	return cErr.WithKind(cerrors.KindInternal)
+}
+
+func (s *Service) wrapUserNotFoundError(err error) error {
+	return cerrors.NewErrorWithUserMessage(ercodes.UserNotFound, err, "Пользователь не найден").WithKind(cerrors.KindNotFound)
 }
 
 func (s *Service) wrapScanError(err error) error {
-	return cerrors.NewErrorWithUserMessage(ercodes.PostgresScan, err, "Ошибка работы с базой данных")
+	return cerrors.NewErrorWithUserMessage(ercodes.PostgresScan, err, "Ошибка работы с базой данных").WithKind(cerrors.KindInternal)
 }
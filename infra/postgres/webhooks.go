@@ -0,0 +1,317 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+	"x-bank-users/cerrors"
+	"x-bank-users/core/webhooks"
+	"x-bank-users/entity"
+	"x-bank-users/ercodes"
+)
+
+func (s *Service) CreateWebhookSubscription(ctx context.Context, subscription webhooks.Subscription) (webhooks.Subscription, error) {
+	const query = `
+INSERT INTO webhook_subscriptions (url, secret, events, active)
+VALUES (@url, @secret, @events, @active)
+RETURNING id, "createdAt"`
+
+	if subscription.Events == nil {
+		subscription.Events = []string{}
+	}
+
+	err := s.querier(ctx).QueryRowContext(ctx, query, pgx.NamedArgs{
+		"url":    subscription.Url,
+		"secret": subscription.Secret,
+		"events": subscription.Events,
+		"active": subscription.Active,
+	},
+	).Scan(&subscription.Id, &subscription.CreatedAt)
+	if err != nil {
+		return webhooks.Subscription{}, s.wrapQueryError(err)
+	}
+
+	return subscription, nil
+}
+
+func (s *Service) GetWebhookSubscriptions(ctx context.Context) ([]webhooks.Subscription, error) {
+	const query = `SELECT id, url, secret, events, active, "createdAt" FROM webhook_subscriptions ORDER BY id`
+
+	return s.queryWebhookSubscriptions(ctx, query, pgx.NamedArgs{})
+}
+
+func (s *Service) GetActiveWebhookSubscriptions(ctx context.Context, eventType string) ([]webhooks.Subscription, error) {
+	const query = `
+SELECT id, url, secret, events, active, "createdAt"
+FROM webhook_subscriptions
+WHERE active AND (cardinality(events) = 0 OR @eventType = ANY(events))
+ORDER BY id`
+
+	return s.queryWebhookSubscriptions(ctx, query, pgx.NamedArgs{
+		"eventType": eventType,
+	})
+}
+
+func (s *Service) queryWebhookSubscriptions(ctx context.Context, query string, args pgx.NamedArgs) ([]webhooks.Subscription, error) {
+	rows, err := s.querier(ctx).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	typeMap := pgtype.NewMap()
+
+	var subscriptions []webhooks.Subscription
+	for rows.Next() {
+		var subscription webhooks.Subscription
+		err = rows.Scan(&subscription.Id, &subscription.Url, &subscription.Secret, typeMap.SQLScanner(&subscription.Events), &subscription.Active, &subscription.CreatedAt)
+		if err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return subscriptions, nil
+}
+
+func (s *Service) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	const query = `DELETE FROM webhook_subscriptions WHERE id = @id`
+
+	result, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id": id,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+	if affected == 0 {
+		return cerrors.NewErrorWithUserMessage(ercodes.WebhookSubscriptionNotFound, nil, "Подписка не найдена").WithKind(cerrors.KindNotFound)
+	}
+
+	return nil
+}
+
+func (s *Service) AddWebhookDelivery(ctx context.Context, delivery webhooks.Delivery) error {
+	const query = `
+INSERT INTO webhook_deliveries ("subscriptionId", "eventId", "eventType", attempt, "statusCode", error, "durationMs")
+VALUES (@subscriptionId, @eventId, @eventType, @attempt, @statusCode, @error, @durationMs)`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"subscriptionId": delivery.SubscriptionId,
+		"eventId":        delivery.EventId,
+		"eventType":      delivery.EventType,
+		"attempt":        delivery.Attempt,
+		"statusCode":     delivery.StatusCode,
+		"error":          delivery.Error,
+		"durationMs":     delivery.Duration.Milliseconds(),
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetWebhookDeliveries(ctx context.Context, subscriptionId int64, limit int) ([]webhooks.Delivery, error) {
+	const query = `
+SELECT id, "subscriptionId", "eventId", "eventType", attempt, "statusCode", error, "durationMs", "createdAt"
+FROM webhook_deliveries
+WHERE "subscriptionId" = @subscriptionId
+ORDER BY "createdAt" DESC, id DESC
+LIMIT @limit`
+
+	rows, err := s.querier(ctx).QueryContext(ctx, query, pgx.NamedArgs{
+		"subscriptionId": subscriptionId,
+		"limit":          limit,
+	},
+	)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deliveries []webhooks.Delivery
+	for rows.Next() {
+		var delivery webhooks.Delivery
+		var durationMs int64
+		err = rows.Scan(&delivery.Id, &delivery.SubscriptionId, &delivery.EventId, &delivery.EventType, &delivery.Attempt,
+			&delivery.StatusCode, &delivery.Error, &durationMs, &delivery.CreatedAt)
+		if err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		delivery.Duration = time.Duration(durationMs) * time.Millisecond
+		deliveries = append(deliveries, delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Service) AddWebhookDeadLetter(ctx context.Context, deadLetter webhooks.DeadLetter) error {
+	const query = `
+INSERT INTO webhook_dead_letters ("subscriptionId", "eventId", "eventType", payload, attempts, "lastError")
+VALUES (@subscriptionId, @eventId, @eventType, @payload, @attempts, @lastError)`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"subscriptionId": deadLetter.SubscriptionId,
+		"eventId":        deadLetter.EventId,
+		"eventType":      deadLetter.EventType,
+		"payload":        deadLetter.Payload,
+		"attempts":       deadLetter.Attempts,
+		"lastError":      deadLetter.LastError,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) GetWebhookDeadLetters(ctx context.Context, limit int) ([]webhooks.DeadLetter, error) {
+	const query = `
+SELECT id, "subscriptionId", "eventId", "eventType", payload, attempts, "lastError", "createdAt"
+FROM webhook_dead_letters
+ORDER BY "createdAt" DESC, id DESC
+LIMIT @limit`
+
+	rows, err := s.querier(ctx).QueryContext(ctx, query, pgx.NamedArgs{
+		"limit": limit,
+	},
+	)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var deadLetters []webhooks.DeadLetter
+	for rows.Next() {
+		var deadLetter webhooks.DeadLetter
+		err = rows.Scan(&deadLetter.Id, &deadLetter.SubscriptionId, &deadLetter.EventId, &deadLetter.EventType,
+			&deadLetter.Payload, &deadLetter.Attempts, &deadLetter.LastError, &deadLetter.CreatedAt)
+		if err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return deadLetters, nil
+}
+
+func (s *Service) EnqueueWebhookJobs(ctx context.Context, event entity.Event, subscriptionIds []int64) error {
+	const query = `
+INSERT INTO webhook_jobs ("subscriptionId", "eventId", event)
+SELECT unnest(@subscriptionIds::BIGINT[]), @eventId, @event
+ON CONFLICT ("subscriptionId", "eventId") DO NOTHING`
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	_, err = s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"subscriptionIds": subscriptionIds,
+		"eventId":         event.Id,
+		"event":           data,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) ClaimWebhookJobs(ctx context.Context, limit int, lease time.Duration) ([]webhooks.Job, error) {
+	const query = `
+WITH claimed AS (
+    UPDATE webhook_jobs
+    SET "nextAttemptAt" = @leaseUntil
+    WHERE id IN (SELECT id
+                 FROM webhook_jobs
+                 WHERE "completedAt" IS NULL AND "nextAttemptAt" <= @now
+                 ORDER BY "nextAttemptAt", id
+                 LIMIT @limit FOR UPDATE SKIP LOCKED)
+    RETURNING id, "subscriptionId", attempts, event
+)
+SELECT claimed.id, claimed.attempts, claimed.event, ws.id, ws.url, ws.secret
+FROM claimed
+JOIN webhook_subscriptions ws ON ws.id = claimed."subscriptionId"
+ORDER BY claimed.id`
+
+	now := time.Now()
+	rows, err := s.querier(ctx).QueryContext(ctx, query, pgx.NamedArgs{
+		"now":        now,
+		"leaseUntil": now.Add(lease),
+		"limit":      limit,
+	},
+	)
+	if err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var jobs []webhooks.Job
+	for rows.Next() {
+		var job webhooks.Job
+		var event []byte
+		err = rows.Scan(&job.Id, &job.Attempts, &event, &job.Subscription.Id, &job.Subscription.Url, &job.Subscription.Secret)
+		if err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		if err = json.Unmarshal(event, &job.Event); err != nil {
+			return nil, s.wrapScanError(err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, s.wrapQueryError(err)
+	}
+
+	return jobs, nil
+}
+
+func (s *Service) CompleteWebhookJob(ctx context.Context, id int64) error {
+	const query = `UPDATE webhook_jobs SET "completedAt" = current_timestamp, attempts = attempts + 1 WHERE id = @id`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id": id,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
+
+func (s *Service) RetryWebhookJob(ctx context.Context, id int64, nextAttemptAt time.Time) error {
+	const query = `UPDATE webhook_jobs SET attempts = attempts + 1, "nextAttemptAt" = @nextAttemptAt WHERE id = @id`
+
+	_, err := s.querier(ctx).ExecContext(ctx, query, pgx.NamedArgs{
+		"id":            id,
+		"nextAttemptAt": nextAttemptAt,
+	},
+	)
+	if err != nil {
+		return s.wrapQueryError(err)
+	}
+
+	return nil
+}
@@ -33,7 +33,7 @@ func (s *Service) GenerateString(_ context.Context, set string, size int) (strin
 func (s *Service) GenerateRandomNum(buf []byte) (uint16, error) {
 	_, err := rand.Read(buf)
 	if err != nil {
-		return 0, cerrors.NewErrorWithUserMessage(ercodes.RandomGeneration, err, "Ошибка генерации случайного числа")
+		return 0, cerrors.NewErrorWithUserMessage(ercodes.RandomGeneration, err, "Ошибка генерации случайного числа").WithKind(cerrors.KindInternal)
 	}
 	
 	num := binary.BigEndian.Uint16(buf)
@@ -6,4 +6,6 @@ const (
 	refreshTokenKey     = "MS-USERS:REFRESH-TOKENS:"
 	userRefreshTokenKey = "MS-USERS:USER-REFRESH-TOKENS:"
 	TwoFaCodeKey        = "MS-USERS:2FA-CODES:"
+	exportKey           = "MS-USERS:EXPORTS:"
+	accessTokensKey     = "MS-USERS:ACCESS-TOKENS-REVOKED:"
 )
@@ -3,11 +3,13 @@ package redis
 import (
 	"context"
 	"errors"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"strconv"
 	"strings"
 	"time"
 	"x-bank-users/cerrors"
+	"x-bank-users/core/web"
 	"x-bank-users/ercodes"
 )
 
@@ -29,6 +31,10 @@ func NewService(password, host string, port int, database, maxCons int) (Service
 		MaxActiveConns: maxCons,
 	})
 
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return Service{}, err
+	}
+
 	if err := client.Ping(context.Background()).Err(); err != nil {
 		return Service{}, err
 	}
@@ -40,7 +46,7 @@ func NewService(password, host string, port int, database, maxCons int) (Service
 
 func (s *Service) SaveActivationCode(ctx context.Context, code string, userId int64, ttl time.Duration) error {
 	if err := s.db.Set(ctx, activationCodeKey+code, userId, ttl).Err(); err != nil {
-		return s.wrapQueryError(err)
+		return s.wrapQueryError(ctx, err)
 	}
 
 	return nil
@@ -50,16 +56,16 @@ func (s *Service) VerifyActivationCode(ctx context.Context, code string) (int64,
 	userId, err := s.db.Get(ctx, activationCodeKey+code).Int64()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, cerrors.NewErrorWithUserMessage(ercodes.ActivationCodeNotFound, nil, "Код активации не найден")
+			return 0, cerrors.NewErrorWithUserMessage(ercodes.ActivationCodeNotFound, nil, "Код активации не найден").WithKind(cerrors.KindNotFound)
 		}
-		return 0, s.wrapQueryError(err)
+		return 0, s.wrapQueryError(ctx, err)
 	}
 	return userId, nil
 }
 
 func (s *Service) SaveRecoveryCode(ctx context.Context, code string, userId int64, ttl time.Duration) error {
 	if err := s.db.Set(ctx, recoveryCodeKey+code, userId, ttl).Err(); err != nil {
-		return s.wrapQueryError(err)
+		return s.wrapQueryError(ctx, err)
 	}
 
 	return nil
@@ -69,9 +75,9 @@ func (s *Service) VerifyRecoveryCode(ctx context.Context, code string) (int64, e
 	userId, err := s.db.Get(ctx, recoveryCodeKey+code).Int64()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, cerrors.NewErrorWithUserMessage(ercodes.RecoveryCodeNotFound, nil, "Код восстановления не найден")
+			return 0, cerrors.NewErrorWithUserMessage(ercodes.RecoveryCodeNotFound, nil, "Код восстановления не найден").WithKind(cerrors.KindNotFound)
 		}
-		return 0, s.wrapQueryError(err)
+		return 0, s.wrapQueryError(ctx, err)
 	}
 
 	return userId, nil
@@ -79,10 +85,10 @@ func (s *Service) VerifyRecoveryCode(ctx context.Context, code string) (int64, e
 
 func (s *Service) SaveRefreshToken(ctx context.Context, token string, userId int64, ttl time.Duration) error {
 	if err := s.db.Set(ctx, refreshTokenKey+token, userId, ttl).Err(); err != nil {
-		return s.wrapQueryError(err)
+		return s.wrapQueryError(ctx, err)
 	}
 	if err := s.db.Set(ctx, userRefreshTokenKey+strconv.FormatInt(userId, 10)+":"+token, true, ttl).Err(); err != nil {
-		return s.wrapQueryError(err)
+		return s.wrapQueryError(ctx, err)
 	}
 
 	return nil
@@ -92,9 +98,9 @@ func (s *Service) VerifyRefreshToken(ctx context.Context, token string) (int64,
 	userId, err := s.db.Get(ctx, refreshTokenKey+token).Int64()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, cerrors.NewErrorWithUserMessage(ercodes.RefreshTokenNotFound, nil, "Токен не найден")
+			return 0, cerrors.NewErrorWithUserMessage(ercodes.RefreshTokenNotFound, nil, "Токен не найден").WithKind(cerrors.KindUnauthenticated)
 		}
-		return 0, s.wrapQueryError(err)
+		return 0, s.wrapQueryError(ctx, err)
 	}
 
 	return userId, nil
@@ -109,7 +115,7 @@ func (s *Service) ExpireAllByUserId(ctx context.Context, userId int64) error {
 	for {
 		keys, cursor, err = s.db.Scan(ctx, cursor, userRefreshTokenKey+strconv.FormatInt(userId, 10)+":*", refreshTokenScanSize).Result()
 		if err != nil {
-			return cerrors.NewErrorWithUserMessage(ercodes.ExpireAllByUserIdError, err, "Ошибка сканирования токенов")
+			return cerrors.NewErrorWithUserMessage(ercodes.ExpireAllByUserIdError, err, "Ошибка сканирования токенов").WithKind(cerrors.KindUnavailable).WithRetryable(true)
 		}
 		for _, key := range keys {
 			token := strings.Split(key, ":")[3]
@@ -125,14 +131,82 @@ func (s *Service) ExpireAllByUserId(ctx context.Context, userId int64) error {
 	}
 
 	if err := s.db.Del(ctx, keysToDelete...).Err(); err != nil {
-		return cerrors.NewErrorWithUserMessage(ercodes.ExpireAllByUserIdError, err, "Ошибка удаления токенов")
+		return cerrors.NewErrorWithUserMessage(ercodes.ExpireAllByUserIdError, err, "Ошибка удаления токенов").WithKind(cerrors.KindUnavailable).WithRetryable(true)
+	}
+	return nil
+}
+
+func (s *Service) GetSessionsByUserId(ctx context.Context, userId int64) ([]web.UserSession, error) {
+	var cursor uint64
+	var keys []string
+	var err error
+	var sessions []web.UserSession
+
+	for {
+		keys, cursor, err = s.db.Scan(ctx, cursor, userRefreshTokenKey+strconv.FormatInt(userId, 10)+":*", refreshTokenScanSize).Result()
+		if err != nil {
+			return nil, s.wrapQueryError(ctx, err)
+		}
+		for _, key := range keys {
+			ttl, err := s.db.TTL(ctx, key).Result()
+			if err != nil {
+				return nil, s.wrapQueryError(ctx, err)
+			}
+			if ttl < 0 {
+				continue
+			}
+			sessions = append(sessions, web.UserSession{
+				ExpiresAt: time.Now().Add(ttl),
+			})
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return sessions, nil
+}
+
+func (s *Service) RevokeAccessTokens(ctx context.Context, userId int64, issuedBefore time.Time, ttl time.Duration) error {
+	if err := s.db.Set(ctx, accessTokensKey+strconv.FormatInt(userId, 10), issuedBefore.Unix(), ttl).Err(); err != nil {
+		return s.wrapQueryError(ctx, err)
+	}
+
+	return nil
+}
+
+func (s *Service) AccessTokensRevokedAt(ctx context.Context, userId int64) (int64, error) {
+	revokedAt, err := s.db.Get(ctx, accessTokensKey+strconv.FormatInt(userId, 10)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, s.wrapQueryError(ctx, err)
+	}
+
+	return revokedAt, nil
+}
+
+func (s *Service) AllowExport(ctx context.Context, userId int64, interval time.Duration) (bool, error) {
+	allowed, err := s.db.SetNX(ctx, exportKey+strconv.FormatInt(userId, 10), true, interval).Result()
+	if err != nil {
+		return false, s.wrapQueryError(ctx, err)
 	}
+
+	return allowed, nil
+}
+
+func (s *Service) ReleaseExport(ctx context.Context, userId int64) error {
+	if err := s.db.Del(ctx, exportKey+strconv.FormatInt(userId, 10)).Err(); err != nil {
+		return s.wrapQueryError(ctx, err)
+	}
+
 	return nil
 }
 
 func (s *Service) Save2FaCode(ctx context.Context, code string, userId int64, ttl time.Duration) error {
 	if err := s.db.Set(ctx, TwoFaCodeKey+code, userId, ttl).Err(); err != nil {
-		return s.wrapQueryError(err)
+		return s.wrapQueryError(ctx, err)
 	}
 
 	return nil
@@ -142,10 +216,14 @@ func (s *Service) Verify2FaCode(ctx context.Context, code string) (int64, error)
 	userId, err := s.db.Get(ctx, TwoFaCodeKey+code).Int64()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return 0, cerrors.NewErrorWithUserMessage(ercodes.TwoFaCodeNotFound, nil, "Код двухфакторной аутентификации не найден")
+			return 0, cerrors.NewErrorWithUserMessage(ercodes.TwoFaCodeNotFound, nil, "Код двухфакторной аутентификации не найден").WithKind(cerrors.KindUnauthenticated)
 		}
-		return 0, s.wrapQueryError(err)
+		return 0, s.wrapQueryError(ctx, err)
 	}
 
 	return userId, nil
 }
+
+func (s *Service) Ping(ctx context.Context) error {
+	return s.db.Ping(ctx).Err()
+}
@@ -1,14 +1,21 @@
 package redis
 
 import (
+	"context"
+	"log/slog"
 	"x-bank-users/cerrors"
 	"x-bank-users/ercodes"
+	"x-bank-users/logging"
 )
 
 func (s *Service) Close() {
 	_ = s.db.Close()
 }
 
-func (s *Service) wrapQueryError(err error) error {
-	return cerrors.NewErrorWithUserMessage(ercodes.RedisQuery, err, "Ошибка работы с базой данных")
+func (s *Service) wrapQueryError(ctx context.Context, err error) error {
+	logging.FromContext(ctx).Error("redis query failed", slog.String("error", err.Error()))
+
+	return cerrors.NewErrorWithUserMessage(ercodes.RedisQuery, err, "Ошибка работы с базой данных").
+		WithKind(cerrors.KindUnavailable).
+		WithRetryable(true)
 }
@@ -4,56 +4,111 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"x-bank-users/cerrors"
 	"x-bank-users/ercodes"
+	"x-bank-users/logging"
 )
 
 type (
 	Service struct {
-		client   *http.Client
-		baseURL  string
+		client      *http.Client
+		baseURL     string
+		credentials *atomic.Pointer[credentials]
+	}
+
+	credentials struct {
 		login    string
 		password string
 	}
 )
 
 func NewService(baseURL, Login, Password string) Service {
-	return Service{
-		client:   &http.Client{},
-		baseURL:  baseURL,
-		login:    Login,
-		password: Password,
+	s := Service{
+		client:      &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		baseURL:     baseURL,
+		credentials: &atomic.Pointer[credentials]{},
 	}
+	s.SetCredentials(Login, Password)
+
+	return s
 }
 
-func (s *Service) Send2FaCode(ctx context.Context, telegramId int64, code string) error {
+func (s *Service) SetCredentials(login, password string) {
+	s.credentials.Store(&credentials{login: login, password: password})
+}
+
+func (s *Service) Send2FaCode(ctx context.Context, telegramId int64, code string) (err error) {
+	defer func() {
+		if err != nil {
+			logging.FromContext(ctx).Warn("telegram 2fa code send failed", slog.Int64("telegramId", telegramId), slog.String("error", errorMessage(err)))
+		}
+	}()
+
 	reqBody, err := json.Marshal(map[string]interface{}{
 		"userId": telegramId,
 		"code":   code,
 	})
 	if err != nil {
-		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, err, "Ошибка отправки кода")
+		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, err, "Ошибка отправки кода").WithKind(cerrors.KindInternal)
 	}
 
 	url := s.baseURL + "/internal/v1/2fa"
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
-		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, err, "Ошибка отправки кода")
+		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, err, "Ошибка отправки кода").WithKind(cerrors.KindInternal)
 	}
 
-	req.SetBasicAuth(s.login, s.password)
+	creds := s.credentials.Load()
+	req.SetBasicAuth(creds.login, creds.password)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, err, "Ошибка отправки кода")
+		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, err, "Ошибка отправки кода").
+			WithKind(cerrors.KindUnavailable).
+			WithRetryable(true)
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, nil, "Ошибка отправки кода")
+		retryable := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+		return cerrors.NewErrorWithUserMessage(ercodes.TelegramSendError, fmt.Errorf("статус ответа %d", resp.StatusCode), "Ошибка отправки кода").
+			WithKind(cerrors.KindUnavailable).
+			WithRetryable(retryable)
+	}
+
+	return nil
+}
+
+func errorMessage(err error) string {
+	var cErr *cerrors.Error
+	if errors.As(err, &cErr) && cErr.Unwrap() != nil {
+		return cErr.Unwrap().Error()
+	}
+	return err.Error()
+}
+
+func (s *Service) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.baseURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("статус ответа %d", resp.StatusCode)
 	}
 
 	return nil
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"x-bank-users/entity"
+)
+
+func TestSendSignsRequest(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	var (
+		headers http.Header
+		body    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := entity.Event{
+		Id:          "0b6f3c1e-8f2a-4c4e-9d43-7a2f3f7e9a10",
+		Type:        entity.EventUserSignedUp,
+		Version:     1,
+		AggregateId: 42,
+		OccurredAt:  time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		Data:        []byte(`{"userId":42}`),
+	}
+
+	service := NewService("")
+	statusCode, err := service.Send(context.Background(), server.URL, secret, event)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if statusCode != http.StatusAccepted {
+		t.Fatalf("status code = %d, want %d", statusCode, http.StatusAccepted)
+	}
+
+	timestamp := headers.Get("X-Webhook-Timestamp")
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("X-Webhook-Timestamp = %q: %v", timestamp, err)
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew < 0 || skew > time.Minute {
+		t.Fatalf("X-Webhook-Timestamp skew = %s", skew)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := headers.Get("X-Webhook-Signature"); got != want {
+		t.Fatalf("X-Webhook-Signature = %q, want %q", got, want)
+	}
+
+	expected := map[string]string{
+		"Content-Type":    "application/json",
+		"X-Event-Id":      event.Id,
+		"X-Event-Type":    event.Type,
+		"X-Event-Version": "1",
+	}
+	for name, value := range expected {
+		if got := headers.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestSendReturnsStatusCodeOfFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	service := NewService("")
+	statusCode, err := service.Send(context.Background(), server.URL, "secret", entity.Event{Id: "id", Type: entity.EventUserDeleted})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if statusCode != http.StatusBadGateway {
+		t.Fatalf("status code = %d, want %d", statusCode, http.StatusBadGateway)
+	}
+}
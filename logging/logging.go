@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+)
+
+type (
+	loggerCtxKey struct{}
+)
+
+var (
+	sensitiveKeys = map[string]bool{
+		"password":      true,
+		"passwordhash":  true,
+		"token":         true,
+		"accesstoken":   true,
+		"refreshtoken":  true,
+		"code":          true,
+		"secret":        true,
+		"authorization": true,
+		"hash":          true,
+		"passportid":    true,
+		"phonenumber":   true,
+	}
+)
+
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}))
+}
+
+func ParseLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("неизвестный уровень логирования %q", level)
+	}
+	return l, nil
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerCtxKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, redactedValue)
+	}
+	return a
+}
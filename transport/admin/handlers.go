@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"x-bank-users/buildinfo"
+	"x-bank-users/core/health"
+)
+
+func (t *Transport) handlerHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": health.StatusOk})
+}
+
+func (t *Transport) handlerReadyz(w http.ResponseWriter, r *http.Request) {
+	report := t.health.Ready(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != health.StatusOk {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, statusCode, report)
+}
+
+func (t *Transport) handlerBuildInfo(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, buildinfo.Get())
+}
+
+func (t *Transport) handlerJobs(w http.ResponseWriter, r *http.Request) {
+	runs, err := t.jobs.GetJobRuns(r.Context())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
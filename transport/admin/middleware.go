@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+)
+
+func (t *Transport) authMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t.isAllowedAddr(r.RemoteAddr) || t.isValidBasicAuth(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if t.login != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	})
+}
+
+func (t *Transport) isAllowedAddr(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range t.allowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Transport) isValidBasicAuth(r *http.Request) bool {
+	if t.login == "" {
+		return false
+	}
+
+	login, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(login), []byte(t.login)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(password), []byte(t.password)) == 1
+}
@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+func (t *Transport) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("GET /metrics", t.metrics)
+
+	mux.HandleFunc("GET /healthz", t.handlerHealthz)
+	mux.HandleFunc("GET /readyz", t.handlerReadyz)
+	mux.HandleFunc("GET /buildinfo", t.handlerBuildInfo)
+	mux.HandleFunc("GET /jobs", t.handlerJobs)
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return t.authMiddleware(mux)
+}
@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"x-bank-users/core/health"
+	"x-bank-users/core/scheduler"
+)
+
+type (
+	JobRunsProvider interface {
+		GetJobRuns(ctx context.Context) ([]scheduler.JobRun, error)
+	}
+
+	Transport struct {
+		metrics http.Handler
+		health  *health.Service
+		jobs    JobRunsProvider
+
+		login           string
+		password        string
+		allowedNetworks []*net.IPNet
+
+		srv *http.Server
+	}
+)
+
+func NewTransport(metrics http.Handler, healthService *health.Service, jobs JobRunsProvider, login, password string, allowedNetworks []string) (Transport, error) {
+	networks := make([]*net.IPNet, 0, len(allowedNetworks))
+	for _, cidr := range allowedNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return Transport{}, fmt.Errorf("некорректная подсеть %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+
+	return Transport{
+		metrics:         metrics,
+		health:          healthService,
+		jobs:            jobs,
+		login:           login,
+		password:        password,
+		allowedNetworks: networks,
+	}, nil
+}
+
+func (t *Transport) Start(addr string) chan error {
+	t.srv = &http.Server{Addr: addr, Handler: t.routes()}
+	ch := make(chan error)
+
+	go func() {
+		ch <- t.srv.ListenAndServe()
+	}()
+
+	return ch
+}
+
+func (t *Transport) Stop(ctx context.Context) error {
+	return t.srv.Shutdown(ctx)
+}
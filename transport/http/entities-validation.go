@@ -2,18 +2,42 @@ package http
 
 import (
 	"net/http"
+	"net/url"
 	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"x-bank-users/vcodes"
+)
+
+const (
+	defaultPersonalDataMinAge = 18
 )
 
 var (
 	isValidEmail = regexp.MustCompile("^.+@.+\\..+$").MatchString
 	isValidLogin = regexp.MustCompile("^[a-z0-9_-]{6,32}$").MatchString
+	isValidPhone = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`).MatchString
+
+	isValidPassportId = map[string]func(string) bool{
+		"RU": regexp.MustCompile(`^[0-9]{10}$`).MatchString,
+		"BY": regexp.MustCompile(`^[A-Z]{2}[0-9]{7}$`).MatchString,
+		"KZ": regexp.MustCompile(`^[A-Z]?[0-9]{8,9}$`).MatchString,
+		"UA": regexp.MustCompile(`^([0-9]{9}|[A-Z]{2}[0-9]{6})$`).MatchString,
+		"US": regexp.MustCompile(`^[A-Z0-9][0-9]{8}$`).MatchString,
+	}
+	isValidPassportIdDefault = regexp.MustCompile(`^[A-Z0-9]{5,20}$`).MatchString
+
+	personalDataMinAge = map[string]int{
+		"RU": 14,
+		"BY": 14,
+	}
 )
 
-func (t *Transport) validate(w http.ResponseWriter, v validatable) bool {
+func (t *Transport) validate(w http.ResponseWriter, r *http.Request, v validatable) bool {
 	ve := v.validate()
 	if len(ve) > 0 {
-		t.errorHandler.setUnprocessableEntityError(w, ve)
+		t.errorHandler.setUnprocessableEntityError(w, r, ve)
 		return false
 	}
 
@@ -24,17 +48,17 @@ func (u *UserDataToSignUp) validate() (ve validationErrors) {
 	ve = make(validationErrors, 0, 3)
 
 	if !isValidEmail(u.Email) {
-		ve.Add("Неверный адрес электронной почты")
+		ve.Add("email", vcodes.InvalidFormat)
 	}
 
 	if !isValidLogin(u.Login) {
-		ve.Add("Неверный логин")
+		ve.Add("login", vcodes.InvalidFormat)
 	}
 
 	if len(u.Password) < 6 {
-		ve.Add("Слишком короткий пароль")
+		ve.AddCount("password", vcodes.TooShort, 6)
 	} else if len(u.Password) > 16 {
-		ve.Add("Слишком длинный пароль")
+		ve.AddCount("password", vcodes.TooLong, 16)
 	}
 
 	return
@@ -44,11 +68,11 @@ func (u *UserDataToSignIn) validate() (ve validationErrors) {
 	ve = make(validationErrors, 0, 2)
 
 	if !isValidLogin(u.Login) {
-		ve.Add("Неверный логин")
+		ve.Add("login", vcodes.InvalidFormat)
 	}
 
 	if len(u.Password) < 6 || len(u.Password) > 16 {
-		ve.Add("Неверный пароль")
+		ve.Add("password", vcodes.InvalidValue)
 	}
 
 	return
@@ -58,26 +82,179 @@ func (u *TelegramBindRequest) validate() (ve validationErrors) {
 	ve = make(validationErrors, 0, 3)
 
 	if u.TelegramId == 0 {
-		ve.Add("Неверный id")
+		ve.Add("id", vcodes.Required)
 	}
 	if len(u.FirstName) == 0 {
-		ve.Add("Неверное имя пользователя")
+		ve.Add("firstname", vcodes.Required)
 	}
 	if len(u.LastName) == 0 {
-		ve.Add("Неверная фамилия пользователя")
+		ve.Add("lastname", vcodes.Required)
 	}
 	if len(u.Username) == 0 {
-		ve.Add("Неверный username")
+		ve.Add("username", vcodes.Required)
 	}
 	if len(u.PhotoUrl) == 0 {
-		ve.Add("Неверный путь к фотографии")
+		ve.Add("photoUrl", vcodes.Required)
 	}
 	if u.AuthDate == 0 {
-		ve.Add("Неверная дата авторизаци")
+		ve.Add("authDate", vcodes.Required)
 	}
 	if len(u.Hash) == 0 {
-		ve.Add("Неверный хэш")
+		ve.Add("hash", vcodes.Required)
 	}
 
 	return
 }
+
+func (u *DeleteAccountRequest) validate() (ve validationErrors) {
+	ve = make(validationErrors, 0, 1)
+
+	if len(u.Password) < 6 || len(u.Password) > 16 {
+		ve.Add("password", vcodes.InvalidValue)
+	}
+
+	return
+}
+
+func (u *WebhookSubscriptionRequest) validate() (ve validationErrors) {
+	ve = make(validationErrors, 0, 1)
+
+	parsed, err := url.Parse(u.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		ve.Add("url", vcodes.InvalidFormat)
+	}
+
+	return
+}
+
+func (u *WorkplaceRequest) validate() (ve validationErrors) {
+	ve = make(validationErrors, 0, 5)
+
+	if len(u.CompanyName) == 0 || len(u.CompanyName) > 128 {
+		ve.Add("companyName", vcodes.InvalidValue)
+	}
+	if len(u.CompanyAddress) == 0 || len(u.CompanyAddress) > 255 {
+		ve.Add("companyAddress", vcodes.InvalidValue)
+	}
+	if len(u.Position) == 0 || len(u.Position) > 128 {
+		ve.Add("position", vcodes.InvalidValue)
+	}
+
+	startDate, err := time.Parse(time.DateOnly, u.StartDate)
+	if err != nil {
+		ve.Add("startDate", vcodes.InvalidFormat)
+		return
+	}
+	if startDate.After(time.Now()) {
+		ve.Add("startDate", vcodes.InFuture)
+	}
+
+	if u.EndDate != nil {
+		endDate, err := time.Parse(time.DateOnly, *u.EndDate)
+		if err != nil {
+			ve.Add("endDate", vcodes.InvalidFormat)
+		} else if !startDate.Before(endDate) {
+			ve.Add("endDate", vcodes.InvalidRange)
+		}
+	}
+
+	return
+}
+
+func (u *UserPersonalDataRequest) validate() (ve validationErrors) {
+	ve = make(validationErrors, 0, 9)
+
+	if _, ok := normalizePhoneNumber(u.PhoneNumber); !ok {
+		ve.Add("phoneNumber", vcodes.InvalidFormat)
+	}
+
+	if !isValidName(u.FirstName, true) {
+		ve.Add("firstName", vcodes.InvalidValue)
+	}
+	if !isValidName(u.LastName, true) {
+		ve.Add("lastName", vcodes.InvalidValue)
+	}
+	if u.FathersName != nil && !isValidName(*u.FathersName, false) {
+		ve.Add("fathersName", vcodes.InvalidValue)
+	}
+
+	address := strings.TrimSpace(u.Address)
+	if len(address) == 0 || utf8.RuneCountInString(address) > 128 {
+		ve.Add("address", vcodes.InvalidValue)
+	}
+
+	if u.Gender != "M" && u.Gender != "F" {
+		ve.Add("gender", vcodes.InvalidValue)
+	}
+
+	if u.country == nil {
+		ve.Add("liveInCountry", vcodes.Unknown)
+	} else {
+		isValid, ok := isValidPassportId[u.country.Alpha2]
+		if !ok {
+			isValid = isValidPassportIdDefault
+		}
+		if !isValid(normalizePassportId(u.PassportId)) {
+			ve.Add("passportId", vcodes.InvalidFormat)
+		}
+	}
+
+	dateOfBirth, err := time.Parse(time.DateOnly, u.DateOfBirth)
+	if err != nil {
+		ve.Add("dateOfBirth", vcodes.InvalidFormat)
+		return
+	}
+
+	now := time.Now()
+	minAge := defaultPersonalDataMinAge
+	if u.country != nil {
+		if age, ok := personalDataMinAge[u.country.Alpha2]; ok {
+			minAge = age
+		}
+	}
+
+	switch {
+	case dateOfBirth.After(now):
+		ve.Add("dateOfBirth", vcodes.InFuture)
+	case dateOfBirth.AddDate(minAge, 0, 0).After(now):
+		ve.AddCount("dateOfBirth", vcodes.TooYoung, minAge)
+	case dateOfBirth.Year() < 1900:
+		ve.Add("dateOfBirth", vcodes.InvalidValue)
+	}
+
+	return
+}
+
+func isValidName(name string, required bool) bool {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return !required
+	}
+	return utf8.RuneCountInString(name) <= 64
+}
+
+func normalizePhoneNumber(phone string) (string, bool) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+
+	normalized := b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+
+	return normalized, isValidPhone(normalized)
+}
+
+func normalizePassportId(passportId string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(passportId))
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"x-bank-users/entity"
+	"x-bank-users/vcodes"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+		ok    bool
+	}{
+		{"+79991234567", "+79991234567", true},
+		{" +7 (999) 123-45-67 ", "+79991234567", true},
+		{"0079991234567", "+79991234567", true},
+		{"+375 29 123 45 67", "+375291234567", true},
+		{"+1234567", "", false},
+		{"+12345678", "+12345678", true},
+		{"+123456789012345", "+123456789012345", true},
+		{"+1234567890123456", "", false},
+		{"+09991234567", "", false},
+		{"89991234567", "", false},
+		{"7+9991234567", "", false},
+		{"+7.999.123.45.67", "", false},
+		{"+7999123456a", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizePhoneNumber(tt.phone)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("normalizePhoneNumber(%q) = %q, %t, want %q, %t", tt.phone, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPassportIdFormats(t *testing.T) {
+	tests := []struct {
+		country    string
+		passportId string
+		valid      bool
+	}{
+		{"RU", "4510 123456", true},
+		{"RU", "4510-123456", true},
+		{"RU", "451012345", false},
+		{"RU", "45101234567", false},
+		{"RU", "AB10123456", false},
+		{"BY", "MP1234567", true},
+		{"BY", "mp 1234567", true},
+		{"BY", "M1234567", false},
+		{"BY", "MP123456", false},
+		{"KZ", "N12345678", true},
+		{"KZ", "123456789", true},
+		{"KZ", "1234567", false},
+		{"KZ", "NN12345678", false},
+		{"UA", "123456789", true},
+		{"UA", "AB123456", true},
+		{"UA", "AB1234567", false},
+		{"US", "123456789", true},
+		{"US", "A12345678", true},
+		{"US", "AB2345678", false},
+		{"DE", "C01X00T47", true},
+		{"DE", "C01X", false},
+		{"DE", "C01X00T47C01X00T47C01X", false},
+		{"DE", "C01X/00T47", false},
+	}
+	for _, tt := range tests {
+		isValid, ok := isValidPassportId[tt.country]
+		if !ok {
+			isValid = isValidPassportIdDefault
+		}
+		if got := isValid(normalizePassportId(tt.passportId)); got != tt.valid {
+			t.Errorf("%s passport %q: valid = %t, want %t", tt.country, tt.passportId, got, tt.valid)
+		}
+	}
+}
+
+func TestPersonalDataDateOfBirth(t *testing.T) {
+	now := time.Now().UTC()
+	date := func(years, days int) string {
+		return now.AddDate(years, 0, days).Format(time.DateOnly)
+	}
+
+	tests := []struct {
+		name        string
+		country     string
+		dateOfBirth string
+		want        validationErrors
+	}{
+		{"adult", "DE", date(-30, 0), nil},
+		{"default minimum age", "DE", date(-18, -1), nil},
+		{"younger than default minimum", "DE", date(-18, 1), validationErrors{{Field: "dateOfBirth", Code: vcodes.TooYoung, count: 18}}},
+		{"country minimum age", "RU", date(-14, -1), nil},
+		{"younger than country minimum", "RU", date(-14, 1), validationErrors{{Field: "dateOfBirth", Code: vcodes.TooYoung, count: 14}}},
+		{"in future", "RU", date(0, 1), validationErrors{{Field: "dateOfBirth", Code: vcodes.InFuture, count: 1}}},
+		{"before 1900", "RU", "1899-12-31", validationErrors{{Field: "dateOfBirth", Code: vcodes.InvalidValue, count: 1}}},
+		{"not a date", "RU", "31.12.1990", validationErrors{{Field: "dateOfBirth", Code: vcodes.InvalidFormat, count: 1}}},
+	}
+	for _, tt := range tests {
+		request := validPersonalDataRequest(tt.country)
+		request.DateOfBirth = tt.dateOfBirth
+
+		got := request.validate()
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: validate() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPersonalDataReportsEveryInvalidField(t *testing.T) {
+	fathersName := ""
+	request := UserPersonalDataRequest{
+		PhoneNumber: "8-999",
+		FathersName: &fathersName,
+		DateOfBirth: "1990-01-01",
+		PassportId:  "12",
+		Gender:      "X",
+		country:     &entity.Country{Alpha2: "RU"},
+	}
+
+	var fields []string
+	for _, fieldError := range request.validate() {
+		fields = append(fields, fieldError.Field)
+	}
+
+	want := []string{"phoneNumber", "firstName", "lastName", "address", "gender", "passportId"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("invalid fields = %v, want %v", fields, want)
+	}
+}
+
+func TestPersonalDataRequiresKnownCountry(t *testing.T) {
+	request := validPersonalDataRequest("RU")
+	request.country = nil
+
+	got := request.validate()
+	want := validationErrors{{Field: "liveInCountry", Code: vcodes.Unknown, count: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("validate() = %+v, want %+v", got, want)
+	}
+}
+
+var validPassportIds = map[string]string{
+	"RU": "4510 123456",
+	"DE": "C01X00T47",
+}
+
+func validPersonalDataRequest(country string) UserPersonalDataRequest {
+	return UserPersonalDataRequest{
+		PhoneNumber: "+7 999 123-45-67",
+		FirstName:   "Иван",
+		LastName:    "Иванов",
+		DateOfBirth: "1990-05-06",
+		PassportId:  validPassportIds[country],
+		Address:     "Москва, ул. Ленина, 1",
+		Gender:      "M",
+		country:     &entity.Country{Alpha2: country},
+	}
+}
@@ -1,5 +1,10 @@
 package http
 
+import (
+	"encoding/json"
+	"x-bank-users/entity"
+)
+
 type (
 	UserDataToSignUp struct {
 		Email    string `json:"email"`
@@ -26,6 +31,10 @@ type (
 		Code string `json:"code"`
 	}
 
+	UserDataToActivate struct {
+		Code string `json:"code"`
+	}
+
 	RefreshRequest struct {
 		RefreshToken string `json:"refreshToken"`
 	}
@@ -52,6 +61,40 @@ type (
 		LiveInCountry string  `json:"liveInCountry"`
 	}
 
+	UserPersonalDataRequest struct {
+		PhoneNumber   string  `json:"phoneNumber"`
+		FirstName     string  `json:"firstName"`
+		LastName      string  `json:"lastName"`
+		FathersName   *string `json:"fathersName"`
+		DateOfBirth   string  `json:"dateOfBirth"`
+		PassportId    string  `json:"passportId"`
+		Address       string  `json:"address"`
+		Gender        string  `json:"gender"`
+		LiveInCountry int64   `json:"liveInCountry"`
+
+		country *entity.Country
+	}
+
+	CountriesResponse struct {
+		Items []entity.Country `json:"items"`
+	}
+
+	HealthResponse struct {
+		Status string `json:"status"`
+	}
+
+	WorkplaceRequest struct {
+		CompanyName    string  `json:"companyName"`
+		CompanyAddress string  `json:"companyAddress"`
+		Position       string  `json:"position"`
+		StartDate      string  `json:"startDate"`
+		EndDate        *string `json:"endDate"`
+	}
+
+	WorkplaceResponse struct {
+		Id int64 `json:"id"`
+	}
+
 	UserPersonalDataResponse struct {
 		PersonalData *UserPersonalData `json:"personalData"`
 	}
@@ -75,4 +118,96 @@ type (
 	UserAuthHistoryResponse struct {
 		Items []UserAuthHistoryResponseItem `json:"items"`
 	}
+
+	TwoFactorMethodResponseItem struct {
+		Type       string `json:"type"`
+		Identifier string `json:"identifier"`
+	}
+
+	SessionResponseItem struct {
+		ExpiresAt string `json:"expiresAt"`
+	}
+
+	UserExportResponse struct {
+		User             UserDataResponse              `json:"user"`
+		PersonalData     *UserPersonalData             `json:"personalData"`
+		Workplaces       []entity.UserWorkplace        `json:"workplaces"`
+		AuthHistory      []UserAuthHistoryResponseItem `json:"authHistory"`
+		TwoFactorMethods []TwoFactorMethodResponseItem `json:"twoFactorMethods"`
+		Sessions         []SessionResponseItem         `json:"sessions"`
+	}
+
+	AuditEventResponseItem struct {
+		Id        int64             `json:"id"`
+		ActorId   *int64            `json:"actorId"`
+		SubjectId int64             `json:"subjectId"`
+		Action    string            `json:"action"`
+		Before    map[string]string `json:"before"`
+		After     map[string]string `json:"after"`
+		Details   map[string]string `json:"details"`
+		Ip        string            `json:"ip"`
+		Agent     string            `json:"agent"`
+		RequestId string            `json:"requestId"`
+		CreatedAt string            `json:"createdAt"`
+	}
+
+	AuditEventsResponse struct {
+		Items []AuditEventResponseItem `json:"items"`
+	}
+
+	WebhookSubscriptionRequest struct {
+		Url    string   `json:"url"`
+		Events []string `json:"events"`
+	}
+
+	WebhookSubscriptionResponse struct {
+		Id        int64    `json:"id"`
+		Url       string   `json:"url"`
+		Secret    string   `json:"secret,omitempty"`
+		Events    []string `json:"events"`
+		Active    bool     `json:"active"`
+		CreatedAt string   `json:"createdAt"`
+	}
+
+	WebhookSubscriptionsResponse struct {
+		Items []WebhookSubscriptionResponse `json:"items"`
+	}
+
+	WebhookDeliveryResponseItem struct {
+		Id         int64   `json:"id"`
+		EventId    string  `json:"eventId"`
+		EventType  string  `json:"eventType"`
+		Attempt    int     `json:"attempt"`
+		StatusCode *int    `json:"statusCode"`
+		Error      *string `json:"error"`
+		DurationMs int64   `json:"durationMs"`
+		CreatedAt  string  `json:"createdAt"`
+	}
+
+	WebhookDeliveriesResponse struct {
+		Items []WebhookDeliveryResponseItem `json:"items"`
+	}
+
+	WebhookDeadLetterResponseItem struct {
+		Id             int64           `json:"id"`
+		SubscriptionId int64           `json:"subscriptionId"`
+		EventId        string          `json:"eventId"`
+		EventType      string          `json:"eventType"`
+		Payload        json.RawMessage `json:"payload"`
+		Attempts       int             `json:"attempts"`
+		LastError      string          `json:"lastError"`
+		CreatedAt      string          `json:"createdAt"`
+	}
+
+	WebhookDeadLettersResponse struct {
+		Items []WebhookDeadLetterResponseItem `json:"items"`
+	}
+
+	DeleteAccountRequest struct {
+		Password string `json:"password"`
+	}
+
+	DeleteAccountResponse struct {
+		DeleteAt string `json:"deleteAt"`
+	}
 )
@@ -2,10 +2,13 @@ package http
 
 import "net/http"
 
-func (t *Transport) corsHandler(allowOrigin, allowHeaders, allowMethods, exposeHeaders string) http.HandlerFunc {
+func (t *Transport) corsHandler(allowHeaders, allowMethods, exposeHeaders string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if allowOrigin != "" {
+		if allowOrigin := t.allowedOrigin(r.Header.Get("Origin")); allowOrigin != "" {
 			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			if allowOrigin != "*" {
+				w.Header().Add("Vary", "Origin")
+			}
 		}
 		if allowMethods != "" {
 			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
@@ -18,3 +21,19 @@ func (t *Transport) corsHandler(allowOrigin, allowHeaders, allowMethods, exposeH
 		}
 	}
 }
+
+func (t *Transport) allowedOrigin(origin string) string {
+	for _, allowed := range *t.corsOrigins.Load() {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && allowed == origin {
+			return origin
+		}
+	}
+	return ""
+}
+
+func (t *Transport) SetCorsOrigins(origins []string) {
+	t.corsOrigins.Store(&origins)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"x-bank-users/entity"
+)
+
+func (t *Transport) handlerGetCountries(w http.ResponseWriter, r *http.Request) {
+	countries, err := t.service.GetCountries(r.Context())
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	etag := `"` + countries.Version + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	response := CountriesResponse{
+		Items: countries.Items,
+	}
+	if response.Items == nil {
+		response.Items = []entity.Country{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -3,21 +3,41 @@ package http
 import (
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+	"x-bank-users/i18n"
+	"x-bank-users/logging"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:x-bank-users:error:"
 )
 
 type (
 	TransportError struct {
-		InternalCode string `json:"internalCode"`
-		DevMessage   string `json:"devMessage"`
-		UserMessage  string `json:"userMessage"`
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+
+		InternalCode string       `json:"internalCode"`
+		UserMessage  string       `json:"userMessage"`
+		DevMessage   string       `json:"devMessage,omitempty"`
+		Stack        string       `json:"stack,omitempty"`
+		Fields       []FieldError `json:"fields,omitempty"`
 	}
 
 	errorHandler struct {
 		defaultStatusCode int
 		statusCodes       map[cerrors.Code]int
+		kindStatusCodes   map[cerrors.Kind]int
+		catalog           i18n.Catalog
+		debug             bool
 	}
 )
 
@@ -28,65 +48,86 @@ func errorMessage(err error) string {
 	return err.Error()
 }
 
-func (h *errorHandler) setTransportError(w http.ResponseWriter, transportError TransportError, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
+func (h *errorHandler) statusCode(code cerrors.Code, kind cerrors.Kind) int {
+	if statusCode, ok := h.kindStatusCodes[kind]; ok {
+		return statusCode
+	}
+	if statusCode, ok := h.statusCodes[code]; ok {
+		return statusCode
+	}
+	return h.defaultStatusCode
+}
+
+func (h *errorHandler) setTransportError(w http.ResponseWriter, r *http.Request, code cerrors.Code, err error, fields []FieldError) {
+	lang := h.catalog.Language(r.Header.Get("Accept-Language"))
+	statusCode := h.statusCode(code, cerrors.KindOf(err))
+
+	internalCode := strconv.FormatInt(int64(code), 10)
+	message := h.catalog.Error(lang, code)
+
+	transportError := TransportError{
+		Type:         problemTypePrefix + internalCode,
+		Title:        http.StatusText(statusCode),
+		Status:       statusCode,
+		Detail:       message,
+		Instance:     r.URL.RequestURI(),
+		InternalCode: internalCode,
+		UserMessage:  message,
+		Fields:       fields,
+	}
+	if h.debug {
+		transportError.DevMessage = errorMessage(err)
+
+		var cErr *cerrors.Error
+		if errors.As(err, &cErr) {
+			transportError.Stack = cErr.StackTrace()
+		}
+	}
+
+	h.logError(r, statusCode, code, err)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("Content-Language", lang)
 	w.WriteHeader(statusCode)
 	_ = json.NewEncoder(w).Encode(&transportError)
 }
 
-func (h *errorHandler) setError(w http.ResponseWriter, err error) {
+func (h *errorHandler) setError(w http.ResponseWriter, r *http.Request, err error) {
 	var cErr *cerrors.Error
 	if !errors.As(err, &cErr) {
-		h.setTransportError(w, TransportError{
-			DevMessage:  errorMessage(err),
-			UserMessage: "Неизвестная ошибка",
-		}, http.StatusBadRequest)
+		h.setTransportError(w, r, ercodes.Unknown, err, nil)
 		return
 	}
 
-	statusCode, ok := h.statusCodes[cErr.Code]
-	if !ok {
-		statusCode = h.defaultStatusCode
-	}
-
-	h.setTransportError(w, TransportError{
-		InternalCode: strconv.FormatInt(int64(cErr.Code), 10),
-		DevMessage:   errorMessage(cErr.Origin),
-		UserMessage:  cErr.UserMessage,
-	}, statusCode)
+	h.setTransportError(w, r, cErr.Code, err, nil)
 }
 
-func (h *errorHandler) setBadRequestError(w http.ResponseWriter, err error) {
-	h.setTransportError(w, TransportError{
-		DevMessage: errorMessage(err), UserMessage: "Ошибка запроса",
-	}, http.StatusBadRequest)
+func (h *errorHandler) setBadRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	h.setTransportError(w, r, ercodes.BadRequest, err, nil)
 }
 
-func (h *errorHandler) setMethodNotAllowedError(w http.ResponseWriter) {
-	h.setTransportError(w, TransportError{
-		UserMessage: "Метод не поддерживается",
-	}, http.StatusMethodNotAllowed)
+func (h *errorHandler) setMethodNotAllowedError(w http.ResponseWriter, r *http.Request) {
+	h.setTransportError(w, r, ercodes.MethodNotAllowed, nil, nil)
 }
 
-func (h *errorHandler) setNotFoundError(w http.ResponseWriter) {
-	h.setTransportError(w, TransportError{
-		UserMessage: "Не найдено",
-	}, http.StatusNotFound)
+func (h *errorHandler) setNotFoundError(w http.ResponseWriter, r *http.Request) {
+	h.setTransportError(w, r, ercodes.NotFound, nil, nil)
 }
 
-func (h *errorHandler) setUnauthorizedError(w http.ResponseWriter, err error) {
-	h.setTransportError(w, TransportError{
-		DevMessage: errorMessage(err), UserMessage: "Не авторизован",
-	}, http.StatusUnauthorized)
+func (h *errorHandler) setUnauthorizedError(w http.ResponseWriter, r *http.Request, err error) {
+	h.setTransportError(w, r, ercodes.Unauthorized, err, nil)
 }
 
-func (h *errorHandler) setUnprocessableEntityError(w http.ResponseWriter, ve validationErrors) {
-	w.WriteHeader(http.StatusUnprocessableEntity)
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(&ve)
+func (h *errorHandler) setUnprocessableEntityError(w http.ResponseWriter, r *http.Request, ve validationErrors) {
+	lang := h.catalog.Language(r.Header.Get("Accept-Language"))
+	for i := range ve {
+		ve[i].Message = h.catalog.Validation(lang, ve[i].Code, ve[i].count)
+	}
+
+	h.setTransportError(w, r, ercodes.Validation, nil, ve)
 }
 
-func (h *errorHandler) setFatalError(w http.ResponseWriter, v interface{}) {
+func (h *errorHandler) setFatalError(w http.ResponseWriter, r *http.Request, v interface{}) {
 	var devMessage string
 
 	switch T := v.(type) {
@@ -98,8 +139,28 @@ func (h *errorHandler) setFatalError(w http.ResponseWriter, v interface{}) {
 		devMessage = "UNKNOWN FATAL ERROR"
 	}
 
-	h.setTransportError(w, TransportError{
-		DevMessage:  devMessage,
-		UserMessage: "Фатальная ошибка",
-	}, http.StatusInternalServerError)
+	h.setTransportError(w, r, ercodes.Fatal, errors.New(devMessage), nil)
+}
+
+func (h *errorHandler) logError(r *http.Request, statusCode int, code cerrors.Code, err error) {
+	level := slog.LevelInfo
+	if statusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	attrs := []slog.Attr{
+		slog.Int64("internalCode", int64(code)),
+		slog.Int("status", statusCode),
+		slog.String("origin", errorMessage(err)),
+	}
+
+	var cErr *cerrors.Error
+	if errors.As(err, &cErr) {
+		attrs = append(attrs, slog.String("kind", cerrors.KindOf(err).String()), slog.Bool("retryable", cerrors.IsRetryable(err)))
+		if stack := cErr.StackTrace(); stack != "" {
+			attrs = append(attrs, slog.String("stack", stack))
+		}
+	}
+
+	logging.FromContext(r.Context()).LogAttrs(r.Context(), level, "request failed", attrs...)
 }
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+	"x-bank-users/i18n"
+)
+
+func newTestErrorHandler(t *testing.T, debug bool) errorHandler {
+	t.Helper()
+
+	catalog, err := i18n.NewCatalog("ru")
+	if err != nil {
+		t.Fatalf("NewCatalog: %v", err)
+	}
+
+	return errorHandler{
+		defaultStatusCode: http.StatusInternalServerError,
+		statusCodes:       errorStatusCodes,
+		kindStatusCodes:   kindStatusCodes,
+		catalog:           catalog,
+		debug:             debug,
+	}
+}
+
+func serveError(t *testing.T, debug bool, err error) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	h := newTestErrorHandler(t, debug)
+	r := httptest.NewRequest(http.MethodGet, "/v1/me/work/7?x=1", nil)
+	r.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+
+	h.setError(w, r, err)
+
+	var body map[string]any
+	if decodeErr := json.NewDecoder(w.Body).Decode(&body); decodeErr != nil {
+		t.Fatalf("decode body: %v", decodeErr)
+	}
+
+	return w, body
+}
+
+func TestSetErrorWritesProblemJSON(t *testing.T) {
+	err := cerrors.NewErrorWithUserMessage(ercodes.WorkplaceNotFound, errors.New("no rows"), "Место работы не найдено").WithKind(cerrors.KindNotFound)
+	w, body := serveError(t, false, err)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if got := w.Header().Get("Content-Type"); got != problemContentType {
+		t.Fatalf("Content-Type = %q, want %q", got, problemContentType)
+	}
+	if got := w.Header().Get("Content-Language"); got != "en" {
+		t.Fatalf("Content-Language = %q, want en", got)
+	}
+
+	internalCode := strconv.FormatInt(int64(ercodes.WorkplaceNotFound), 10)
+	expected := map[string]any{
+		"type":         problemTypePrefix + internalCode,
+		"title":        http.StatusText(http.StatusNotFound),
+		"status":       float64(http.StatusNotFound),
+		"detail":       "Workplace not found",
+		"instance":     "/v1/me/work/7?x=1",
+		"internalCode": internalCode,
+		"userMessage":  "Workplace not found",
+	}
+	for field, want := range expected {
+		if got := body[field]; got != want {
+			t.Errorf("%s = %v, want %v", field, got, want)
+		}
+	}
+}
+
+func TestSetErrorHidesDevMessageWithoutDebug(t *testing.T) {
+	cerrors.SetCaptureStack(true)
+	defer cerrors.SetCaptureStack(false)
+
+	err := cerrors.NewErrorWithUserMessage(ercodes.PostgresQuery, errors.New("connection refused"), "Ошибка базы данных")
+
+	_, body := serveError(t, false, err)
+	for _, field := range []string{"devMessage", "stack"} {
+		if _, ok := body[field]; ok {
+			t.Errorf("%s is exposed without debug: %v", field, body[field])
+		}
+	}
+
+	_, body = serveError(t, true, err)
+	if got, _ := body["devMessage"].(string); got == "" {
+		t.Error("devMessage is empty with debug enabled")
+	}
+	if got, _ := body["stack"].(string); got == "" {
+		t.Error("stack is empty with debug enabled")
+	}
+}
+
+func TestSetErrorMapsUnknownErrors(t *testing.T) {
+	w, body := serveError(t, false, errors.New("boom"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := body["internalCode"]; got != strconv.FormatInt(int64(ercodes.Unknown), 10) {
+		t.Fatalf("internalCode = %v, want %d", got, ercodes.Unknown)
+	}
+}
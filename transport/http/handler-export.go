@@ -0,0 +1,201 @@
+package http
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"x-bank-users/auth"
+	"x-bank-users/core/web"
+	"x-bank-users/logging"
+)
+
+const (
+	exportFormatJSON = "json"
+	exportFormatZIP  = "zip"
+)
+
+func (t *Transport) handlerExportUserData(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatJSON
+	}
+	if format != exportFormatJSON && format != exportFormatZIP {
+		t.errorHandler.setBadRequestError(w, r, errors.New("неизвестный формат выгрузки"))
+		return
+	}
+
+	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
+	if !ok {
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
+		return
+	}
+
+	export, err := t.service.ExportUserData(r.Context(), claims.Sub, format)
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	response := newUserExportResponse(export)
+
+	w.Header().Set("Content-Disposition", `attachment; filename="export.`+format+`"`)
+	if format == exportFormatZIP {
+		w.Header().Set("Content-Type", "application/zip")
+		err = writeUserExportZip(w, response)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(response)
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Error("export response write failed", slog.String("error", err.Error()))
+	}
+}
+
+func newUserExportResponse(export web.UserExport) UserExportResponse {
+	response := UserExportResponse{
+		User: UserDataResponse{
+			Id:         export.User.Id,
+			UUID:       export.User.UUID,
+			Login:      export.User.Login,
+			Email:      export.User.Email,
+			TelegramId: export.User.TelegramId,
+			CreatedAt:  export.User.CreatedAt.Format("2006-01-02"),
+		},
+		Workplaces:       export.Workplaces,
+		AuthHistory:      make([]UserAuthHistoryResponseItem, 0, len(export.AuthHistory)),
+		TwoFactorMethods: make([]TwoFactorMethodResponseItem, 0, len(export.TwoFactorMethods)),
+		Sessions:         make([]SessionResponseItem, 0, len(export.Sessions)),
+	}
+
+	if data := export.PersonalData; data != nil {
+		response.PersonalData = &UserPersonalData{
+			PhoneNumber:   data.PhoneNumber,
+			FirstName:     data.FirstName,
+			LastName:      data.LastName,
+			FathersName:   data.FathersName,
+			DateOfBirth:   data.DateOfBirth.Format("2006-01-02"),
+			PassportId:    data.PassportId,
+			Address:       data.Address,
+			Gender:        data.Gender,
+			LiveInCountry: data.LiveInCountry,
+		}
+	}
+
+	for _, entry := range export.AuthHistory {
+		response.AuthHistory = append(response.AuthHistory, UserAuthHistoryResponseItem{
+			Id:        entry.Id,
+			Agent:     entry.Agent,
+			Ip:        entry.Ip,
+			Timestamp: entry.Timestamp.Format("2006.01.02 15:04:05"),
+		})
+	}
+
+	for _, method := range export.TwoFactorMethods {
+		response.TwoFactorMethods = append(response.TwoFactorMethods, TwoFactorMethodResponseItem{
+			Type:       method.Type,
+			Identifier: method.Identifier,
+		})
+	}
+
+	for _, session := range export.Sessions {
+		response.Sessions = append(response.Sessions, SessionResponseItem{
+			ExpiresAt: session.ExpiresAt.Format("2006.01.02 15:04:05"),
+		})
+	}
+
+	return response
+}
+
+func writeUserExportZip(w io.Writer, export UserExportResponse) error {
+	zw := zip.NewWriter(w)
+
+	telegramId := ""
+	if export.User.TelegramId != nil {
+		telegramId = strconv.FormatInt(*export.User.TelegramId, 10)
+	}
+	tables := []struct {
+		name   string
+		header []string
+		rows   [][]string
+	}{
+		{
+			name:   "user.csv",
+			header: []string{"id", "uuid", "login", "email", "telegramId", "createdAt"},
+			rows: [][]string{{
+				strconv.FormatInt(export.User.Id, 10), export.User.UUID, export.User.Login, export.User.Email,
+				telegramId, export.User.CreatedAt,
+			}},
+		},
+		{
+			name:   "personal_data.csv",
+			header: []string{"phoneNumber", "firstName", "lastName", "fathersName", "dateOfBirth", "passportId", "address", "gender", "liveInCountry"},
+		},
+		{
+			name:   "workplaces.csv",
+			header: []string{"id", "companyName", "companyAddress", "position", "startDate", "endDate"},
+		},
+		{
+			name:   "auth_history.csv",
+			header: []string{"id", "agent", "ip", "timestamp"},
+		},
+		{
+			name:   "two_factor_methods.csv",
+			header: []string{"type", "identifier"},
+		},
+		{
+			name:   "sessions.csv",
+			header: []string{"expiresAt"},
+		},
+	}
+
+	if data := export.PersonalData; data != nil {
+		var fathersName string
+		if data.FathersName != nil {
+			fathersName = *data.FathersName
+		}
+		tables[1].rows = append(tables[1].rows, []string{
+			data.PhoneNumber, data.FirstName, data.LastName, fathersName, data.DateOfBirth,
+			data.PassportId, data.Address, data.Gender, data.LiveInCountry,
+		})
+	}
+	for _, wp := range export.Workplaces {
+		var endDate string
+		if wp.EndDate != nil {
+			endDate = strconv.FormatInt(*wp.EndDate, 10)
+		}
+		tables[2].rows = append(tables[2].rows, []string{
+			strconv.FormatInt(wp.Id, 10), wp.CompanyName, wp.CompanyAddress, wp.Position, strconv.FormatInt(wp.StartDate, 10), endDate,
+		})
+	}
+	for _, entry := range export.AuthHistory {
+		tables[3].rows = append(tables[3].rows, []string{strconv.FormatInt(entry.Id, 10), entry.Agent, entry.Ip, entry.Timestamp})
+	}
+	for _, method := range export.TwoFactorMethods {
+		tables[4].rows = append(tables[4].rows, []string{method.Type, method.Identifier})
+	}
+	for _, session := range export.Sessions {
+		tables[5].rows = append(tables[5].rows, []string{session.ExpiresAt})
+	}
+
+	for _, table := range tables {
+		f, err := zw.Create(table.name)
+		if err != nil {
+			return err
+		}
+
+		cw := csv.NewWriter(f)
+		if err = cw.Write(table.header); err != nil {
+			return err
+		}
+		if err = cw.WriteAll(table.rows); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
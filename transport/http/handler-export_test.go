@@ -0,0 +1,229 @@
+package http
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+	"x-bank-users/auth"
+	"x-bank-users/core/web"
+	"x-bank-users/entity"
+)
+
+type (
+	exportUserStorage struct {
+		web.UserStorage
+	}
+
+	exportSessionStorage struct {
+		web.RefreshTokenStorage
+	}
+
+	exportLimiter struct {
+		allowed bool
+	}
+
+	exportAuditLogger struct {
+		web.AuditLogger
+	}
+)
+
+func (exportUserStorage) GetUserDataById(_ context.Context, id int64) (web.UserData, error) {
+	telegramId := int64(42)
+	return web.UserData{
+		Id:         id,
+		UUID:       "0b7f6c1e-1c4a-4a3e-9d55-1f0c3b6f2a10",
+		Login:      "ivan",
+		Email:      "ivan@example.com",
+		TelegramId: &telegramId,
+		CreatedAt:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (exportUserStorage) GetUserPersonalDataById(context.Context, int64) (*web.UserPersonalData, error) {
+	return &web.UserPersonalData{
+		PhoneNumber:   "+79990000000",
+		FirstName:     "Иван",
+		LastName:      "Иванов",
+		DateOfBirth:   time.Date(1990, 5, 6, 0, 0, 0, 0, time.UTC),
+		PassportId:    "4510 123456",
+		Address:       "Москва, ул. Ленина, 1",
+		Gender:        "male",
+		LiveInCountry: "Россия",
+	}, nil
+}
+
+func (exportUserStorage) GetUserWorkplaces(context.Context, int64) ([]entity.UserWorkplace, error) {
+	return []entity.UserWorkplace{{
+		Id:             3,
+		CompanyName:    "Bank, Inc",
+		CompanyAddress: "Moscow",
+		Position:       "Analyst",
+		StartDate:      1672531200,
+	}}, nil
+}
+
+func (exportUserStorage) GetUserAuthHistory(context.Context, int64) ([]web.UserAuthHistoryData, error) {
+	return []web.UserAuthHistoryData{
+		{Id: 2, Agent: "curl/8.0", Ip: "10.0.0.2", Timestamp: time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{Id: 1, Agent: "Mozilla \"quoted\"", Ip: "10.0.0.1", Timestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil
+}
+
+func (exportSessionStorage) GetSessionsByUserId(context.Context, int64) ([]web.UserSession, error) {
+	return []web.UserSession{{ExpiresAt: time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)}}, nil
+}
+
+func (l exportLimiter) AllowExport(context.Context, int64, time.Duration) (bool, error) {
+	return l.allowed, nil
+}
+
+func (exportLimiter) ReleaseExport(context.Context, int64) error {
+	return nil
+}
+
+func (exportAuditLogger) LogEvent(context.Context, web.AuditEvent) error {
+	return nil
+}
+
+func serveExport(t *testing.T, allowed bool, format string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	service := web.NewService(
+		exportUserStorage{}, nil, nil, nil, exportSessionStorage{}, nil, nil, nil, nil,
+		exportLimiter{allowed: allowed}, exportAuditLogger{},
+		web.AuthSettings{}, web.RateLimits{ExportInterval: time.Hour}, 0, nil, nil,
+	)
+	tr := &Transport{
+		service:      service,
+		errorHandler: newTestErrorHandler(t, false),
+		claimsCtxKey: "CLAIMS",
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/me/export?format="+format, nil)
+	r = r.WithContext(context.WithValue(r.Context(), tr.claimsCtxKey, &auth.Claims{Sub: 1}))
+	w := httptest.NewRecorder()
+
+	tr.handlerExportUserData(w, r)
+
+	return w
+}
+
+func readExportZip(t *testing.T, body []byte) ([]string, map[string][][]string) {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+
+	names := make([]string, 0, len(zr.File))
+	tables := make(map[string][][]string, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		records, err := csv.NewReader(rc).ReadAll()
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+		names = append(names, f.Name)
+		tables[f.Name] = records
+	}
+
+	return names, tables
+}
+
+func TestExportZipLayout(t *testing.T) {
+	w := serveExport(t, true, exportFormatZIP)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/zip" {
+		t.Fatalf("Content-Type = %q, want application/zip", got)
+	}
+	if got, want := w.Header().Get("Content-Disposition"), `attachment; filename="export.zip"`; got != want {
+		t.Fatalf("Content-Disposition = %q, want %q", got, want)
+	}
+
+	names, tables := readExportZip(t, w.Body.Bytes())
+
+	wantNames := []string{"user.csv", "personal_data.csv", "workplaces.csv", "auth_history.csv", "two_factor_methods.csv", "sessions.csv"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("files = %v, want %v", names, wantNames)
+	}
+
+	want := map[string][][]string{
+		"user.csv": {
+			{"id", "uuid", "login", "email", "telegramId", "createdAt"},
+			{"1", "0b7f6c1e-1c4a-4a3e-9d55-1f0c3b6f2a10", "ivan", "ivan@example.com", "42", "2024-01-02"},
+		},
+		"personal_data.csv": {
+			{"phoneNumber", "firstName", "lastName", "fathersName", "dateOfBirth", "passportId", "address", "gender", "liveInCountry"},
+			{"+79990000000", "Иван", "Иванов", "", "1990-05-06", "4510 123456", "Москва, ул. Ленина, 1", "male", "Россия"},
+		},
+		"workplaces.csv": {
+			{"id", "companyName", "companyAddress", "position", "startDate", "endDate"},
+			{"3", "Bank, Inc", "Moscow", "Analyst", "1672531200", ""},
+		},
+		"auth_history.csv": {
+			{"id", "agent", "ip", "timestamp"},
+			{"2", "curl/8.0", "10.0.0.2", "2024.03.04 05:06:07"},
+			{"1", "Mozilla \"quoted\"", "10.0.0.1", "2024.03.01 00:00:00"},
+		},
+		"two_factor_methods.csv": {
+			{"type", "identifier"},
+			{"telegram", "42"},
+		},
+		"sessions.csv": {
+			{"expiresAt"},
+			{"2024.04.01 12:00:00"},
+		},
+	}
+	for name, rows := range want {
+		if !reflect.DeepEqual(tables[name], rows) {
+			t.Errorf("%s = %q, want %q", name, tables[name], rows)
+		}
+	}
+}
+
+func TestExportJSONIsAttachment(t *testing.T) {
+	w := serveExport(t, true, exportFormatJSON)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", got)
+	}
+	if got, want := w.Header().Get("Content-Disposition"), `attachment; filename="export.json"`; got != want {
+		t.Fatalf("Content-Disposition = %q, want %q", got, want)
+	}
+}
+
+func TestExportIsRateLimited(t *testing.T) {
+	w := serveExport(t, false, exportFormatZIP)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "" {
+		t.Fatalf("Content-Disposition = %q on a rejected export", got)
+	}
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	w := serveExport(t, true, "xml")
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"x-bank-users/core/health"
+)
+
+func (t *Transport) handlerHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(HealthResponse{Status: health.StatusOk})
+}
+
+func (t *Transport) handlerReadyz(w http.ResponseWriter, r *http.Request) {
+	report := t.health.Ready(r.Context())
+
+	statusCode := http.StatusOK
+	if report.Status != health.StatusOk {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(report)
+}
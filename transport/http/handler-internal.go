@@ -0,0 +1,227 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"x-bank-users/core/web"
+	"x-bank-users/core/webhooks"
+)
+
+func (t *Transport) handlerGetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := web.AuditEventsFilter{
+		Action: query.Get("action"),
+	}
+
+	var err error
+	if filter.ActorId, err = parseOptionalInt64(query.Get("actorId")); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+	if filter.SubjectId, err = parseOptionalInt64(query.Get("subjectId")); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+	if filter.From, err = parseOptionalTime(query.Get("from")); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+	if filter.To, err = parseOptionalTime(query.Get("to")); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			t.errorHandler.setBadRequestError(w, r, err)
+			return
+		}
+	}
+	if v := query.Get("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil {
+			t.errorHandler.setBadRequestError(w, r, err)
+			return
+		}
+	}
+
+	events, err := t.service.GetAuditEvents(r.Context(), filter)
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	response := AuditEventsResponse{
+		Items: make([]AuditEventResponseItem, 0, len(events)),
+	}
+	for _, event := range events {
+		response.Items = append(response.Items, AuditEventResponseItem{
+			Id:        event.Id,
+			ActorId:   event.ActorId,
+			SubjectId: event.SubjectId,
+			Action:    event.Action,
+			Before:    event.Before,
+			After:     event.After,
+			Details:   event.Details,
+			Ip:        event.Ip,
+			Agent:     event.Agent,
+			RequestId: event.RequestId,
+			CreatedAt: event.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func parseOptionalInt64(s string) (*int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func parseOptionalTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (t *Transport) handlerGetWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := t.webhooks.GetSubscriptions(r.Context())
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	response := WebhookSubscriptionsResponse{
+		Items: make([]WebhookSubscriptionResponse, 0, len(subscriptions)),
+	}
+	for _, subscription := range subscriptions {
+		item := newWebhookSubscriptionResponse(subscription)
+		item.Secret = ""
+		response.Items = append(response.Items, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (t *Transport) handlerCreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var request WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	if !t.validate(w, r, &request) {
+		return
+	}
+
+	subscription, err := t.webhooks.CreateSubscription(r.Context(), request.Url, request.Events)
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newWebhookSubscriptionResponse(subscription))
+}
+
+func (t *Transport) handlerDeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	if err = t.webhooks.DeleteSubscription(r.Context(), id); err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (t *Transport) handlerGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	deliveries, err := t.webhooks.GetDeliveries(r.Context(), id)
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	response := WebhookDeliveriesResponse{
+		Items: make([]WebhookDeliveryResponseItem, 0, len(deliveries)),
+	}
+	for _, delivery := range deliveries {
+		response.Items = append(response.Items, WebhookDeliveryResponseItem{
+			Id:         delivery.Id,
+			EventId:    delivery.EventId,
+			EventType:  delivery.EventType,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Error:      delivery.Error,
+			DurationMs: delivery.Duration.Milliseconds(),
+			CreatedAt:  delivery.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (t *Transport) handlerGetWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	deadLetters, err := t.webhooks.GetDeadLetters(r.Context())
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	response := WebhookDeadLettersResponse{
+		Items: make([]WebhookDeadLetterResponseItem, 0, len(deadLetters)),
+	}
+	for _, deadLetter := range deadLetters {
+		response.Items = append(response.Items, WebhookDeadLetterResponseItem{
+			Id:             deadLetter.Id,
+			SubscriptionId: deadLetter.SubscriptionId,
+			EventId:        deadLetter.EventId,
+			EventType:      deadLetter.EventType,
+			Payload:        deadLetter.Payload,
+			Attempts:       deadLetter.Attempts,
+			LastError:      deadLetter.LastError,
+			CreatedAt:      deadLetter.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func newWebhookSubscriptionResponse(subscription webhooks.Subscription) WebhookSubscriptionResponse {
+	return WebhookSubscriptionResponse{
+		Id:        subscription.Id,
+		Url:       subscription.Url,
+		Secret:    subscription.Secret,
+		Events:    subscription.Events,
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -4,64 +4,85 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"x-bank-users/auth"
 	"x-bank-users/entity"
 )
 
-func (t *Transport) handlerNotFound(w http.ResponseWriter, _ *http.Request) {
-	t.errorHandler.setNotFoundError(w)
+func (t *Transport) handlerNotFound(w http.ResponseWriter, r *http.Request) {
+	t.errorHandler.setNotFoundError(w, r)
 }
 
 func (t *Transport) handlerSignUp(w http.ResponseWriter, r *http.Request) {
 	userData := UserDataToSignUp{}
 
 	if err := json.NewDecoder(r.Body).Decode(&userData); err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
 		return
 	}
 
-	if !t.validate(w, &userData) {
+	if !t.validate(w, r, &userData) {
 		return
 	}
 
 	if err := t.service.SignUp(r.Context(), userData.Login, userData.Password, userData.Email); err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
+func (t *Transport) handlerActivate(w http.ResponseWriter, r *http.Request) {
+	userDataToActivate := UserDataToActivate{}
+	if err := json.NewDecoder(r.Body).Decode(&userDataToActivate); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	if err := t.service.Activate(r.Context(), userDataToActivate.Code); err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (t *Transport) handlerSignIn(w http.ResponseWriter, r *http.Request) {
 	userDataToSignIn := UserDataToSignIn{}
 	if err := json.NewDecoder(r.Body).Decode(&userDataToSignIn); err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
 		return
 	}
 
-	if !t.validate(w, &userDataToSignIn) {
+	if !t.validate(w, r, &userDataToSignIn) {
 		return
 	}
 
-	agent := r.Header.Get("User-Agent")
-	ip := r.Header.Get("X-Real-Ip")
+	agent := userAgent(r)
+	ip := t.clientIp(r)
 
 	signInResult, err := t.service.SignIn(r.Context(), userDataToSignIn.Login, userDataToSignIn.Password, agent, ip)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.observeAuth("sign_in", err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
 	token, err := t.authorizer.Authorize(r.Context(), signInResult.AccessClaims)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.observeAuth("sign_in", err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 	signInResponse := SignInResponse{}
 
 	if signInResult.AccessClaims.Is2FAToken {
+		t.metrics.ObserveAuth("sign_in", "2fa_required")
 		signInResponse.TwoFaDemand = string(token)
 	} else {
+		t.observeAuth("sign_in", nil)
 		signInResponse.Tokens.AccessToken = string(token)
 		signInResponse.Tokens.RefreshToken = signInResult.RefreshToken
 	}
@@ -69,7 +90,7 @@ func (t *Transport) handlerSignIn(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(signInResponse)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 }
@@ -79,29 +100,31 @@ func (t *Transport) handlerSignIn2FA(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewDecoder(r.Body).Decode(&userDataToSignIn2FA)
 	if err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
 		return
 	}
 
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
 	code := userDataToSignIn2FA.Code
-	agent := r.Header.Get("User-Agent")
-	ip := r.Header.Get("X-Real-Ip")
+	agent := userAgent(r)
+	ip := t.clientIp(r)
 
 	signInResult, err := t.service.SignIn2FA(r.Context(), *claims, code, agent, ip)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.observeAuth("sign_in_2fa", err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
 	token, err := t.authorizer.Authorize(r.Context(), signInResult.AccessClaims)
+	t.observeAuth("sign_in_2fa", err)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -113,7 +136,7 @@ func (t *Transport) handlerSignIn2FA(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(signInResponse)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 }
@@ -122,18 +145,20 @@ func (t *Transport) handlerRefresh(w http.ResponseWriter, r *http.Request) {
 	var request RefreshRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
 		return
 	}
 
 	signInResult, err := t.service.Refresh(r.Context(), request.RefreshToken)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.observeAuth("refresh", err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 	token, err := t.authorizer.Authorize(r.Context(), signInResult.AccessClaims)
+	t.observeAuth("refresh", err)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -147,7 +172,7 @@ func (t *Transport) handlerRefresh(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(refreshResponse)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 }
@@ -157,7 +182,7 @@ func (t *Transport) handlerGetUserPersonalData(w http.ResponseWriter, r *http.Re
 	var response UserPersonalDataResponse
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
@@ -166,7 +191,7 @@ func (t *Transport) handlerGetUserPersonalData(w http.ResponseWriter, r *http.Re
 	data, err := t.service.GetUserPersonalData(r.Context(), userId)
 
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -190,30 +215,38 @@ func (t *Transport) handlerGetUserPersonalData(w http.ResponseWriter, r *http.Re
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 }
 
 func (t *Transport) handlerAddUserPersonalData(w http.ResponseWriter, r *http.Request) {
-	var userData entity.UserPersonalData
-	err := json.NewDecoder(r.Body).Decode(&userData)
+	var request UserPersonalDataRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	if request.country, err = t.service.GetCountry(r.Context(), request.LiveInCountry); err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+	if !t.validate(w, r, &request) {
 		return
 	}
 
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
 	userId := claims.Sub
 
-	err = t.service.AddUserPersonalData(r.Context(), userId, userData)
+	err = t.service.AddUserPersonalData(r.Context(), userId, request.toEntity())
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -225,7 +258,7 @@ func (t *Transport) handlerGetUserData(w http.ResponseWriter, r *http.Request) {
 
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
@@ -233,7 +266,7 @@ func (t *Transport) handlerGetUserData(w http.ResponseWriter, r *http.Request) {
 
 	data, err := t.service.GetUserData(r.Context(), userId)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -249,31 +282,61 @@ func (t *Transport) handlerGetUserData(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(userData)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 }
 
+func (t *Transport) handlerDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var request DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	if !t.validate(w, r, &request) {
+		return
+	}
+
+	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
+	if !ok {
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
+		return
+	}
+
+	deleteAt, err := t.service.DeleteAccount(r.Context(), claims.Sub, request.Password)
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(DeleteAccountResponse{
+		DeleteAt: deleteAt.Format("2006.01.02 15:04:05"),
+	})
+}
+
 func (t *Transport) handlerTelegramBind(w http.ResponseWriter, r *http.Request) {
 	var request TelegramBindRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
 		return
 	}
 
-	if !t.validate(w, &request) {
+	if !t.validate(w, r, &request) {
 		return
 	}
 
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
 	if err = t.service.BindTelegram(r.Context(), &request.TelegramId, claims.Sub); err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -283,12 +346,12 @@ func (t *Transport) handlerTelegramBind(w http.ResponseWriter, r *http.Request)
 func (t *Transport) handlerTelegramDelete(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
 	if err := t.service.DeleteTelegram(r.Context(), claims.Sub); err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -298,14 +361,14 @@ func (t *Transport) handlerTelegramDelete(w http.ResponseWriter, r *http.Request
 func (t *Transport) handlerAuthHistory(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
 	userId := claims.Sub
 	authHistory, err := t.service.GetAuthHistory(r.Context(), userId)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -327,7 +390,7 @@ func (t *Transport) handlerAuthHistory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 }
@@ -335,7 +398,7 @@ func (t *Transport) handlerAuthHistory(w http.ResponseWriter, r *http.Request) {
 func (t *Transport) handlerGetWorkplaces(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
@@ -343,7 +406,7 @@ func (t *Transport) handlerGetWorkplaces(w http.ResponseWriter, r *http.Request)
 
 	resp, err := t.service.GetWorkplaces(r.Context(), userId)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
@@ -352,25 +415,115 @@ func (t *Transport) handlerGetWorkplaces(w http.ResponseWriter, r *http.Request)
 }
 
 func (t *Transport) handlerAddWorkplace(w http.ResponseWriter, r *http.Request) {
-	var request entity.Workplace
+	var request WorkplaceRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		t.errorHandler.setBadRequestError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
 		return
 	}
+	if !t.validate(w, r, &request) {
+		return
+	}
+
 	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
 	if !ok {
-		t.errorHandler.setError(w, errors.New("отсутствуют claims в контексте"))
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
 		return
 	}
 
 	userId := claims.Sub
 
-	err = t.service.AddWorkplace(r.Context(), userId, request)
+	id, err := t.service.AddWorkplace(r.Context(), userId, request.toEntity())
+	if err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(WorkplaceResponse{Id: id})
+}
+
+func (t *Transport) handlerUpdateWorkplace(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		t.errorHandler.setError(w, err)
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	var request WorkplaceRequest
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+	if !t.validate(w, r, &request) {
+		return
+	}
+
+	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
+	if !ok {
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
+		return
+	}
+
+	if err = t.service.UpdateWorkplace(r.Context(), claims.Sub, id, request.toEntity()); err != nil {
+		t.errorHandler.setError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func (t *Transport) handlerDeleteWorkplace(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		t.errorHandler.setBadRequestError(w, r, err)
+		return
+	}
+
+	claims, ok := r.Context().Value(t.claimsCtxKey).(*auth.Claims)
+	if !ok {
+		t.errorHandler.setError(w, r, errors.New("отсутствуют claims в контексте"))
+		return
+	}
+
+	if err = t.service.DeleteWorkplace(r.Context(), claims.Sub, id); err != nil {
+		t.errorHandler.setError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (u *UserPersonalDataRequest) toEntity() entity.UserPersonalData {
+	phoneNumber, _ := normalizePhoneNumber(u.PhoneNumber)
+
+	var fathersName *string
+	if u.FathersName != nil {
+		if name := strings.TrimSpace(*u.FathersName); name != "" {
+			fathersName = &name
+		}
+	}
+
+	return entity.UserPersonalData{
+		PhoneNumber:     phoneNumber,
+		FirstName:       strings.TrimSpace(u.FirstName),
+		LastName:        strings.TrimSpace(u.LastName),
+		FathersName:     fathersName,
+		DateOfBirth:     u.DateOfBirth,
+		PassportId:      normalizePassportId(u.PassportId),
+		Address:         strings.TrimSpace(u.Address),
+		Gender:          u.Gender,
+		LiveInCountryId: u.LiveInCountry,
+	}
+}
+
+func (u *WorkplaceRequest) toEntity() entity.Workplace {
+	return entity.Workplace{
+		CompanyName:    u.CompanyName,
+		CompanyAddress: u.CompanyAddress,
+		Position:       u.Position,
+		StartDate:      u.StartDate,
+		EndDate:        u.EndDate,
+	}
+}
@@ -36,7 +36,7 @@ func (R *HS512) Authorize(_ context.Context, claims auth.Claims) ([]byte, error)
 
 	claimsJSON, err := json.Marshal(claims)
 	if err != nil {
-		return nil, cerrors.NewErrorWithUserMessage(ercodes.HS512Authorization, err, "Ошибка преобразования payload")
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.HS512Authorization, err, "Ошибка преобразования payload").WithKind(cerrors.KindInternal)
 	}
 	payload := base64.RawURLEncoding.EncodeToString(claimsJSON)
 
@@ -44,7 +44,7 @@ func (R *HS512) Authorize(_ context.Context, claims auth.Claims) ([]byte, error)
 
 	_, err = mac.Write([]byte(signData))
 	if err != nil {
-		return nil, cerrors.NewErrorWithUserMessage(ercodes.HS512Authorization, err, "Ошибка при подписывании токена")
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.HS512Authorization, err, "Ошибка при подписывании токена").WithKind(cerrors.KindInternal)
 	}
 	token := signData + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 	return []byte(token), nil
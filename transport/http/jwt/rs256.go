@@ -12,6 +12,7 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 	"x-bank-users/auth"
 	"x-bank-users/cerrors"
@@ -20,12 +21,46 @@ import (
 
 type (
 	RS256 struct {
-		PrivateKey *rsa.PrivateKey
-		PublicKey  *rsa.PublicKey
+		keys *atomic.Pointer[rs256Keys]
+	}
+
+	rs256Keys struct {
+		privateKey        *rsa.PrivateKey
+		publicKey         *rsa.PublicKey
+		previousPublicKey *rsa.PublicKey
 	}
 )
 
 func NewRS256(pathPrivateKey, pathPublicKey string) (RS256, error) {
+	keys, err := readRS256Keys(pathPrivateKey, pathPublicKey)
+	if err != nil {
+		return RS256{}, err
+	}
+
+	r := RS256{keys: &atomic.Pointer[rs256Keys]{}}
+	r.keys.Store(keys)
+
+	return r, nil
+}
+
+func (R *RS256) Reload(pathPrivateKey, pathPublicKey string) error {
+	keys, err := readRS256Keys(pathPrivateKey, pathPublicKey)
+	if err != nil {
+		return err
+	}
+
+	current := R.keys.Load()
+	if !current.publicKey.Equal(keys.publicKey) {
+		keys.previousPublicKey = current.publicKey
+	} else {
+		keys.previousPublicKey = current.previousPublicKey
+	}
+
+	R.keys.Store(keys)
+	return nil
+}
+
+func readRS256Keys(pathPrivateKey, pathPublicKey string) (*rs256Keys, error) {
 	privateKey, err := func(path string) (*rsa.PrivateKey, error) {
 		data, err := os.ReadFile(path)
 		if err != nil {
@@ -46,7 +81,7 @@ func NewRS256(pathPrivateKey, pathPublicKey string) (RS256, error) {
 	}(pathPrivateKey)
 
 	if err != nil {
-		return RS256{}, err
+		return nil, err
 	}
 
 	publicKey, err := func(path string) (*rsa.PublicKey, error) {
@@ -74,12 +109,16 @@ func NewRS256(pathPrivateKey, pathPublicKey string) (RS256, error) {
 	}(pathPublicKey)
 
 	if err != nil {
-		return RS256{}, err
+		return nil, err
 	}
 
-	return RS256{
-		PrivateKey: privateKey,
-		PublicKey:  publicKey,
+	if !privateKey.PublicKey.Equal(publicKey) {
+		return nil, errors.New("Публичный ключ не соответствует приватному")
+	}
+
+	return &rs256Keys{
+		privateKey: privateKey,
+		publicKey:  publicKey,
 	}, nil
 }
 
@@ -88,16 +127,16 @@ func (R *RS256) Authorize(ctx context.Context, claims auth.Claims) ([]byte, erro
 
 	claimsJSON, err := json.Marshal(claims)
 	if err != nil {
-		return nil, cerrors.NewErrorWithUserMessage(ercodes.RS256Authorization, err, "Ошибка преобразования payload")
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.RS256Authorization, err, "Ошибка преобразования payload").WithKind(cerrors.KindInternal)
 	}
 	payload := base64.RawURLEncoding.EncodeToString(claimsJSON)
 
 	signData := header + "." + payload
 	hashed := sha256.Sum256([]byte(signData))
 
-	signature, err := rsa.SignPKCS1v15(nil, R.PrivateKey, crypto.SHA256, hashed[:])
+	signature, err := rsa.SignPKCS1v15(nil, R.keys.Load().privateKey, crypto.SHA256, hashed[:])
 	if err != nil {
-		return nil, cerrors.NewErrorWithUserMessage(ercodes.RS256Authorization, err, "Ошибка при подписывании токена")
+		return nil, cerrors.NewErrorWithUserMessage(ercodes.RS256Authorization, err, "Ошибка при подписывании токена").WithKind(cerrors.KindInternal)
 	}
 
 	token := header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
@@ -120,7 +159,11 @@ func (R *RS256) VerifyAuthorization(ctx context.Context, authorization []byte) (
 
 	hashed := sha256.Sum256([]byte(signData))
 
-	err = rsa.VerifyPKCS1v15(R.PublicKey, crypto.SHA256, hashed[:], providedSignature)
+	keys := R.keys.Load()
+	err = rsa.VerifyPKCS1v15(keys.publicKey, crypto.SHA256, hashed[:], providedSignature)
+	if err != nil && keys.previousPublicKey != nil {
+		err = rsa.VerifyPKCS1v15(keys.previousPublicKey, crypto.SHA256, hashed[:], providedSignature)
+	}
 	if err != nil {
 		return auth.Claims{}, cerrors.NewErrorWithUserMessage(ercodes.RS256Authorization, err, "Токен не валиден")
 	}
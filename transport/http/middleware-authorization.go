@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"x-bank-users/core/web"
 )
 
 func (t *Transport) authMiddleware(allow2Fa bool) middleware {
@@ -12,27 +13,36 @@ func (t *Transport) authMiddleware(allow2Fa bool) middleware {
 		return func(w http.ResponseWriter, r *http.Request) {
 			header := r.Header.Get("Authorization")
 			if header == "" {
-				t.errorHandler.setUnauthorizedError(w, errors.New("отсутствует заголовок Authorization"))
+				t.errorHandler.setUnauthorizedError(w, r, errors.New("отсутствует заголовок Authorization"))
 				return
 			}
 			parts := strings.Split(header, " ")
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				t.errorHandler.setUnauthorizedError(w, errors.New("неверный формат заголовка Authorization"))
+				t.errorHandler.setUnauthorizedError(w, r, errors.New("неверный формат заголовка Authorization"))
 				return
 			}
 			token := parts[1]
 			claims, err := t.authorizer.VerifyAuthorization(r.Context(), []byte(token))
 			if err != nil {
-				t.errorHandler.setUnauthorizedError(w, err)
+				t.errorHandler.setUnauthorizedError(w, r, err)
 				return
 			}
 
 			if !allow2Fa && claims.Is2FAToken {
-				t.errorHandler.setUnauthorizedError(w, errors.New("требуется 2FA"))
+				t.errorHandler.setUnauthorizedError(w, r, errors.New("требуется 2FA"))
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), t.claimsCtxKey, &claims)
+			if err = t.service.VerifyAccessClaims(r.Context(), claims); err != nil {
+				t.errorHandler.setError(w, r, err)
+				return
+			}
+
+			meta := web.RequestMetaFromContext(r.Context())
+			meta.ActorId = &claims.Sub
+
+			ctx := context.WithValue(web.WithRequestMeta(r.Context(), meta), t.claimsCtxKey, &claims)
+			ctx = withLogUserId(ctx, claims.Sub)
 			handlerFunc(w, r.WithContext(ctx))
 		}
 	}
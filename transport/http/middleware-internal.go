@@ -0,0 +1,26 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+)
+
+func (t *Transport) internalAuthMiddleware(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		login, password, ok := r.BasicAuth()
+		if !ok {
+			t.errorHandler.setUnauthorizedError(w, r, errors.New("отсутствуют данные basic авторизации"))
+			return
+		}
+
+		if t.internalLogin == "" ||
+			subtle.ConstantTimeCompare([]byte(login), []byte(t.internalLogin)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(password), []byte(t.internalPassword)) != 1 {
+			t.errorHandler.setUnauthorizedError(w, r, errors.New("неверные данные basic авторизации"))
+			return
+		}
+
+		h(w, r)
+	}
+}
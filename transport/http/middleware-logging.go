@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net/http"
+	"time"
+	"x-bank-users/logging"
+)
+
+type (
+	statusRecorder struct {
+		http.ResponseWriter
+		status int
+	}
+
+	requestLogCtxKey struct{}
+
+	requestLog struct {
+		userId *int64
+	}
+)
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (t *Transport) loggingHandler(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestId := requestIdFromRequest(r)
+		r.Header.Set("X-Request-Id", requestId)
+
+		_, route := mux.Handler(r)
+		logger := t.logger.With(slog.String("requestId", requestId))
+		if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.IsValid() {
+			logger = logger.With(slog.String("traceId", spanContext.TraceID().String()))
+		}
+		info := &requestLog{}
+
+		ctx := logging.WithLogger(r.Context(), logger)
+		ctx = context.WithValue(ctx, requestLogCtxKey{}, info)
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		mux.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+
+		latency := time.Since(start)
+		t.metrics.ObserveRequest(r.Method, route, recorder.status, latency)
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("route", route),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Duration("latency", latency),
+		}
+		if info.userId != nil {
+			attrs = append(attrs, slog.Int64("userId", *info.userId))
+		}
+		logger.LogAttrs(ctx, slog.LevelInfo, "http request", attrs...)
+	})
+}
+
+func withLogUserId(ctx context.Context, userId int64) context.Context {
+	if info, ok := ctx.Value(requestLogCtxKey{}).(*requestLog); ok {
+		info.userId = &userId
+	}
+	return logging.WithLogger(ctx, logging.FromContext(ctx).With(slog.Int64("userId", userId)))
+}
@@ -6,7 +6,7 @@ func (t *Transport) panicMiddleware(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				t.errorHandler.setFatalError(w, err)
+				t.errorHandler.setFatalError(w, r, err)
 			}
 		}()
 		h(w, r)
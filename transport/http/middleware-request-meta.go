@@ -0,0 +1,74 @@
+package http
+
+import (
+	"github.com/google/uuid"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+	"x-bank-users/core/web"
+)
+
+const (
+	maxAgentLength = 255
+)
+
+var requestIdRe = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+func (t *Transport) requestMetaMiddleware(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestId := requestIdFromRequest(r)
+		w.Header().Set("X-Request-Id", requestId)
+
+		ctx := web.WithRequestMeta(r.Context(), web.RequestMeta{
+			Ip:        t.clientIp(r),
+			Agent:     userAgent(r),
+			RequestId: requestId,
+		})
+		h(w, r.WithContext(ctx))
+	}
+}
+
+func requestIdFromRequest(r *http.Request) string {
+	requestId := r.Header.Get("X-Request-Id")
+	if !requestIdRe.MatchString(requestId) {
+		requestId = uuid.New().String()
+	}
+	return requestId
+}
+
+func (t *Transport) clientIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	remoteIp := net.ParseIP(host)
+	if remoteIp == nil {
+		return ""
+	}
+
+	if t.isTrustedProxy(remoteIp) {
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-Ip"))); ip != nil {
+			return ip.String()
+		}
+	}
+	return remoteIp.String()
+}
+
+func (t *Transport) isTrustedProxy(ip net.IP) bool {
+	for _, network := range t.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func userAgent(r *http.Request) string {
+	agent := strings.ToValidUTF8(r.UserAgent(), "")
+	if utf8.RuneCountInString(agent) <= maxAgentLength {
+		return agent
+	}
+	return string([]rune(agent)[:maxAgentLength])
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIpTrustsHeaderOnlyFromProxies(t *testing.T) {
+	networks, err := parseNetworks([]string{"10.0.0.0/8", "::1/128"})
+	if err != nil {
+		t.Fatalf("parseNetworks: %v", err)
+	}
+	transport := &Transport{trustedProxies: networks}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		realIp     string
+		want       string
+	}{
+		{"trusted proxy", "10.1.2.3:5000", "203.0.113.7", "203.0.113.7"},
+		{"trusted ipv6 proxy", "[::1]:5000", "2001:db8::1", "2001:db8::1"},
+		{"untrusted caller", "198.51.100.4:5000", "203.0.113.7", "198.51.100.4"},
+		{"trusted proxy with invalid header", "10.1.2.3:5000", "not-an-ip", "10.1.2.3"},
+		{"trusted proxy without header", "10.1.2.3:5000", "", "10.1.2.3"},
+		{"malformed remote address", "garbage", "203.0.113.7", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.realIp != "" {
+				r.Header.Set("X-Real-Ip", tt.realIp)
+			}
+
+			if got := transport.clientIp(r); got != tt.want {
+				t.Fatalf("clientIp = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNetworksRejectsBareAddress(t *testing.T) {
+	if _, err := parseNetworks([]string{"10.0.0.1"}); err == nil {
+		t.Fatal("parseNetworks accepted an address without a prefix length")
+	}
+}
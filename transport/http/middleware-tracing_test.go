@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"x-bank-users/auth"
+	"x-bank-users/core/web"
+)
+
+const (
+	testTraceId      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpanId = "00f067aa0ba902b7"
+)
+
+type (
+	staticAuthorizer struct {
+		auth.Authorizer
+	}
+
+	noRevokedTokens struct{}
+
+	passthroughTransactor struct{}
+
+	discardMetrics struct{}
+
+	workplaceStorage struct {
+		web.UserStorage
+
+		err     error
+		spanCtx trace.SpanContext
+	}
+)
+
+func (staticAuthorizer) VerifyAuthorization(context.Context, []byte) (auth.Claims, error) {
+	return auth.Claims{Sub: 1, IssuedAt: time.Now().Unix()}, nil
+}
+
+func (noRevokedTokens) RevokeAccessTokens(context.Context, int64, time.Time, time.Duration) error {
+	return nil
+}
+
+func (noRevokedTokens) AccessTokensRevokedAt(context.Context, int64) (int64, error) {
+	return 0, nil
+}
+
+func (passthroughTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (discardMetrics) ObserveRequest(string, string, int, time.Duration) {}
+
+func (discardMetrics) ObserveAuth(string, string) {}
+
+func (s *workplaceStorage) DeleteUserWorkplace(ctx context.Context, _, _ int64) error {
+	s.spanCtx = trace.SpanContextFromContext(ctx)
+	return s.err
+}
+
+func serveTraced(t *testing.T, storageErr error) (int, *workplaceStorage, sdktrace.ReadOnlySpan) {
+	t.Helper()
+
+	storage := &workplaceStorage{err: storageErr}
+	service := web.NewService(
+		storage, nil, nil, nil, nil, noRevokedTokens{}, nil, nil, nil, nil, exportAuditLogger{},
+		web.AuthSettings{}, web.RateLimits{}, 0, passthroughTransactor{}, nil,
+	)
+
+	recorder := tracetest.NewSpanRecorder()
+	tr := &Transport{
+		service:        service,
+		authorizer:     staticAuthorizer{},
+		errorHandler:   newTestErrorHandler(t, false),
+		claimsCtxKey:   "CLAIMS",
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		metrics:        discardMetrics{},
+		tracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		propagator:     propagation.TraceContext{},
+		corsOrigins:    &atomic.Pointer[[]string]{},
+	}
+	tr.SetCorsOrigins(nil)
+
+	r := httptest.NewRequest(http.MethodDelete, "/v1/me/work/7", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	r.Header.Set("Traceparent", "00-"+testTraceId+"-"+testParentSpanId+"-01")
+	w := httptest.NewRecorder()
+
+	tr.routes().ServeHTTP(w, r)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want one server span", len(spans))
+	}
+
+	return w.Code, storage, spans[0]
+}
+
+func TestTracingHandlerContinuesIncomingTrace(t *testing.T) {
+	status, storage, span := serveTraced(t, nil)
+	if status != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", status, http.StatusNoContent)
+	}
+
+	if got := span.SpanContext().TraceID().String(); got != testTraceId {
+		t.Fatalf("trace id = %s, want %s", got, testTraceId)
+	}
+	if got := span.Parent().SpanID().String(); got != testParentSpanId {
+		t.Fatalf("parent span id = %s, want %s", got, testParentSpanId)
+	}
+	if !span.Parent().IsRemote() {
+		t.Fatal("parent span context must be remote")
+	}
+	if storage.spanCtx.TraceID() != span.SpanContext().TraceID() {
+		t.Fatalf("storage trace id = %s, want %s", storage.spanCtx.TraceID(), span.SpanContext().TraceID())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("span kind = %s, want %s", span.SpanKind(), trace.SpanKindServer)
+	}
+	if got, want := span.Name(), "DELETE /v1/me/work/{id}"; got != want {
+		t.Fatalf("span name = %q, want %q", got, want)
+	}
+	if span.Status().Code != codes.Unset {
+		t.Fatalf("span status = %s, want %s", span.Status().Code, codes.Unset)
+	}
+
+	attributes := attribute.NewSet(span.Attributes()...)
+	if value, _ := attributes.Value("http.route"); value.AsString() != "DELETE /v1/me/work/{id}" {
+		t.Fatalf("http.route = %q", value.AsString())
+	}
+	if value, _ := attributes.Value("http.response.status_code"); value.AsInt64() != http.StatusNoContent {
+		t.Fatalf("http.response.status_code = %d, want %d", value.AsInt64(), http.StatusNoContent)
+	}
+}
+
+func TestTracingHandlerMarksServerErrors(t *testing.T) {
+	status, _, span := serveTraced(t, errors.New("connection reset"))
+	if status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", status, http.StatusInternalServerError)
+	}
+
+	if span.Status().Code != codes.Error {
+		t.Fatalf("span status = %s, want %s", span.Status().Code, codes.Error)
+	}
+}
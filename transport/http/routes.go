@@ -2,48 +2,74 @@ package http
 
 import (
 	"net/http"
-	_ "net/http/pprof"
 )
 
 func (t *Transport) routes() http.Handler {
-	corsHandler := t.corsHandler("*", "*", "*", "")
+	corsHandler := t.corsHandler("*", "*", "")
 	corsMiddleware := t.corsMiddleware(corsHandler)
 
 	defaultMiddlewareGroup := middlewareGroup{
 		t.panicMiddleware,
 		corsMiddleware,
+		t.requestMetaMiddleware,
 	}
 
 	signIn2FaMiddlewareGroup := middlewareGroup{
 		t.panicMiddleware,
 		corsMiddleware,
+		t.requestMetaMiddleware,
 		t.authMiddleware(true),
 	}
 
 	userMiddlewareGroup := middlewareGroup{
 		t.panicMiddleware,
 		corsMiddleware,
+		t.requestMetaMiddleware,
 		t.authMiddleware(false),
 	}
 
+	probeMiddlewareGroup := middlewareGroup{
+		t.panicMiddleware,
+	}
+
+	internalMiddlewareGroup := middlewareGroup{
+		t.panicMiddleware,
+		t.requestMetaMiddleware,
+		t.internalAuthMiddleware,
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", defaultMiddlewareGroup.Apply(t.handlerNotFound))
 	mux.HandleFunc("OPTIONS /", corsHandler)
 
+	mux.HandleFunc("GET /healthz", probeMiddlewareGroup.Apply(t.handlerHealthz))
+	mux.HandleFunc("GET /readyz", probeMiddlewareGroup.Apply(t.handlerReadyz))
+
 	mux.HandleFunc("POST /v1/auth/sign-up", defaultMiddlewareGroup.Apply(t.handlerSignUp))
+	mux.HandleFunc("POST /v1/auth/activation", defaultMiddlewareGroup.Apply(t.handlerActivate))
 	mux.HandleFunc("POST /v1/auth/sign-in", defaultMiddlewareGroup.Apply(t.handlerSignIn))
 	mux.HandleFunc("POST /v1/auth/sign-in/2fa", signIn2FaMiddlewareGroup.Apply(t.handlerSignIn2FA))
 	mux.HandleFunc("POST /v1/auth/refresh", defaultMiddlewareGroup.Apply(t.handlerRefresh))
 
-	//TODO: add GET countries
-	//TODO: add endpoint to get and set workplaces
+	mux.HandleFunc("GET /v1/countries", defaultMiddlewareGroup.Apply(t.handlerGetCountries))
 
 	mux.HandleFunc("GET /v1/me/personal-data", userMiddlewareGroup.Apply(t.handlerGetUserPersonalData))
 	mux.HandleFunc("PUT /v1/me/personal-data", userMiddlewareGroup.Apply(t.handlerAddUserPersonalData))
 	mux.HandleFunc("GET /v1/me", userMiddlewareGroup.Apply(t.handlerGetUserData))
+	mux.HandleFunc("DELETE /v1/me", userMiddlewareGroup.Apply(t.handlerDeleteAccount))
+	mux.HandleFunc("GET /v1/me/export", userMiddlewareGroup.Apply(t.handlerExportUserData))
 	mux.HandleFunc("GET /v1/me/auth-history", userMiddlewareGroup.Apply(t.handlerAuthHistory))
 	mux.HandleFunc("GET /v1/me/work", userMiddlewareGroup.Apply(t.handlerGetWorkplaces))
 	mux.HandleFunc("POST /v1/me/work", userMiddlewareGroup.Apply(t.handlerAddWorkplace))
+	mux.HandleFunc("PUT /v1/me/work/{id}", userMiddlewareGroup.Apply(t.handlerUpdateWorkplace))
+	mux.HandleFunc("DELETE /v1/me/work/{id}", userMiddlewareGroup.Apply(t.handlerDeleteWorkplace))
+
+	mux.HandleFunc("GET /internal/v1/audit-events", internalMiddlewareGroup.Apply(t.handlerGetAuditEvents))
+	mux.HandleFunc("GET /internal/v1/webhooks", internalMiddlewareGroup.Apply(t.handlerGetWebhookSubscriptions))
+	mux.HandleFunc("POST /internal/v1/webhooks", internalMiddlewareGroup.Apply(t.handlerCreateWebhookSubscription))
+	mux.HandleFunc("DELETE /internal/v1/webhooks/{id}", internalMiddlewareGroup.Apply(t.handlerDeleteWebhookSubscription))
+	mux.HandleFunc("GET /internal/v1/webhooks/{id}/deliveries", internalMiddlewareGroup.Apply(t.handlerGetWebhookDeliveries))
+	mux.HandleFunc("GET /internal/v1/webhooks/dead-letters", internalMiddlewareGroup.Apply(t.handlerGetWebhookDeadLetters))
 
-	return mux
+	return t.tracingHandler(mux, t.loggingHandler(mux))
 }
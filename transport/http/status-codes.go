@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+)
+
+var (
+	errorStatusCodes = map[cerrors.Code]int{
+		ercodes.UserNotFound:                http.StatusNotFound,
+		ercodes.ActivationCodeNotFound:      http.StatusNotFound,
+		ercodes.BcryptHashing:               http.StatusInternalServerError,
+		ercodes.RandomGeneration:            http.StatusInternalServerError,
+		ercodes.HS512Authorization:          http.StatusUnauthorized,
+		ercodes.RS256Authorization:          http.StatusUnauthorized,
+		ercodes.WrongPassword:               http.StatusUnauthorized,
+		ercodes.Invalid2FACode:              http.StatusUnauthorized,
+		ercodes.LoginAlreadyTaken:           http.StatusConflict,
+		ercodes.EmailAlreadyTaken:           http.StatusConflict,
+		ercodes.PostgresQuery:               http.StatusServiceUnavailable,
+		ercodes.PostgresScan:                http.StatusInternalServerError,
+		ercodes.RedisQuery:                  http.StatusServiceUnavailable,
+		ercodes.RecoveryCodeNotFound:        http.StatusNotFound,
+		ercodes.RefreshTokenNotFound:        http.StatusUnauthorized,
+		ercodes.TwoFaCodeNotFound:           http.StatusUnauthorized,
+		ercodes.ExpireAllByUserIdError:      http.StatusServiceUnavailable,
+		ercodes.InvalidLoginOrPassword:      http.StatusUnauthorized,
+		ercodes.TelegramSendError:           http.StatusServiceUnavailable,
+		ercodes.ExportRateLimited:           http.StatusTooManyRequests,
+		ercodes.Encryption:                  http.StatusInternalServerError,
+		ercodes.PassportAlreadyTaken:        http.StatusConflict,
+		ercodes.UnknownEventType:            http.StatusUnprocessableEntity,
+		ercodes.WebhookSubscriptionNotFound: http.StatusNotFound,
+		ercodes.WorkplaceNotFound:           http.StatusNotFound,
+		ercodes.CountryNotFound:             http.StatusUnprocessableEntity,
+		ercodes.Validation:                  http.StatusUnprocessableEntity,
+		ercodes.BadRequest:                  http.StatusBadRequest,
+		ercodes.MethodNotAllowed:            http.StatusMethodNotAllowed,
+		ercodes.NotFound:                    http.StatusNotFound,
+		ercodes.Unauthorized:                http.StatusUnauthorized,
+		ercodes.Fatal:                       http.StatusInternalServerError,
+		ercodes.Unknown:                     http.StatusInternalServerError,
+		ercodes.WorkplaceAddressMismatch:    http.StatusConflict,
+		ercodes.AccessTokenRevoked:          http.StatusUnauthorized,
+	}
+)
+
+var (
+	kindStatusCodes = map[cerrors.Kind]int{
+		cerrors.KindNotFound:        http.StatusNotFound,
+		cerrors.KindConflict:        http.StatusConflict,
+		cerrors.KindUnauthenticated: http.StatusUnauthorized,
+		cerrors.KindUnavailable:     http.StatusServiceUnavailable,
+		cerrors.KindInternal:        http.StatusInternalServerError,
+	}
+)
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"testing"
+	"x-bank-users/cerrors"
+	"x-bank-users/ercodes"
+)
+
+func TestEveryErrorCodeHasStatusCode(t *testing.T) {
+	for _, code := range ercodes.All() {
+		statusCode, ok := errorStatusCodes[code]
+		if !ok {
+			t.Errorf("нет http статуса для кода ошибки %d", code)
+			continue
+		}
+		if statusCode < 400 || statusCode > 599 {
+			t.Errorf("код ошибки %d: статус %d не является ошибкой", code, statusCode)
+		}
+	}
+}
+
+func TestKindOverridesCodeStatus(t *testing.T) {
+	h := errorHandler{
+		defaultStatusCode: http.StatusInternalServerError,
+		statusCodes:       errorStatusCodes,
+		kindStatusCodes:   kindStatusCodes,
+	}
+
+	tests := []struct {
+		code cerrors.Code
+		kind cerrors.Kind
+		want int
+	}{
+		{ercodes.UserNotFound, cerrors.KindUnspecified, http.StatusNotFound},
+		{ercodes.UserNotFound, cerrors.KindNotFound, http.StatusNotFound},
+		{ercodes.PostgresQuery, cerrors.KindUnspecified, http.StatusServiceUnavailable},
+		{ercodes.PostgresQuery, cerrors.KindConflict, http.StatusConflict},
+		{ercodes.Unknown, cerrors.KindUnauthenticated, http.StatusUnauthorized},
+		{cerrors.Code(1), cerrors.KindUnspecified, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := h.statusCode(tt.code, tt.kind); got != tt.want {
+			t.Errorf("statusCode(%d, %d) = %d, want %d", tt.code, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestMissingUserIsNotFound(t *testing.T) {
+	err := cerrors.NewErrorWithUserMessage(ercodes.UserNotFound, sql.ErrNoRows, "Пользователь не найден").WithKind(cerrors.KindNotFound)
+	w, body := serveError(t, false, err)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if got, want := body["internalCode"], strconv.FormatInt(int64(ercodes.UserNotFound), 10); got != want {
+		t.Fatalf("internalCode = %v, want %s", got, want)
+	}
+	if got := body["detail"]; got != "User not found" {
+		t.Fatalf("detail = %v, want User not found", got)
+	}
+}
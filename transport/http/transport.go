@@ -2,37 +2,99 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"net"
 	"net/http"
+	"sync/atomic"
+	"time"
 	"x-bank-users/auth"
 	"x-bank-users/cerrors"
+	"x-bank-users/core/health"
 	"x-bank-users/core/web"
-	"x-bank-users/ercodes"
+	"x-bank-users/core/webhooks"
+	"x-bank-users/i18n"
 )
 
 type (
 	Transport struct {
 		service      web.Service
+		webhooks     webhooks.Service
+		health       *health.Service
 		authorizer   auth.Authorizer
 		errorHandler errorHandler
 
-		srv *http.Server
+		srv        *http.Server
+		drainDelay time.Duration
+
+		corsOrigins    *atomic.Pointer[[]string]
+		trustedProxies []*net.IPNet
 
 		claimsCtxKey string
+
+		internalLogin    string
+		internalPassword string
+
+		logger         *slog.Logger
+		metrics        Metrics
+		tracerProvider trace.TracerProvider
+		propagator     propagation.TextMapPropagator
+	}
+
+	Metrics interface {
+		ObserveRequest(method, route string, status int, duration time.Duration)
+		ObserveAuth(flow, outcome string)
 	}
 )
 
-func NewTransport(service web.Service, authorizer auth.Authorizer) Transport {
-	return Transport{
+func NewTransport(service web.Service, webhooksService webhooks.Service, healthService *health.Service, drainDelay time.Duration, authorizer auth.Authorizer, internalLogin, internalPassword string, corsOrigins, trustedProxies []string, catalog i18n.Catalog, debug bool, logger *slog.Logger, metrics Metrics) (Transport, error) {
+	networks, err := parseNetworks(trustedProxies)
+	if err != nil {
+		return Transport{}, err
+	}
+
+	t := Transport{
 		service:    service,
+		webhooks:   webhooksService,
+		health:     healthService,
 		authorizer: authorizer,
 		errorHandler: errorHandler{
-			defaultStatusCode: http.StatusBadRequest,
-			statusCodes: map[cerrors.Code]int{
-				ercodes.BcryptHashing: http.StatusInternalServerError,
-			},
+			defaultStatusCode: http.StatusInternalServerError,
+			statusCodes:       errorStatusCodes,
+			kindStatusCodes:   kindStatusCodes,
+			catalog:           catalog,
+			debug:             debug,
 		},
-		claimsCtxKey: "CLAIMS",
+		drainDelay:       drainDelay,
+		claimsCtxKey:     "CLAIMS",
+		internalLogin:    internalLogin,
+		internalPassword: internalPassword,
+		logger:           logger,
+		metrics:          metrics,
+		corsOrigins:      &atomic.Pointer[[]string]{},
+		trustedProxies:   networks,
+		tracerProvider:   otel.GetTracerProvider(),
+		propagator:       otel.GetTextMapPropagator(),
+	}
+	t.SetCorsOrigins(corsOrigins)
+
+	return t, nil
+}
+
+func parseNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная подсеть %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
 	}
+
+	return networks, nil
 }
 
 func (t *Transport) Start(addr string) chan error {
@@ -47,5 +109,23 @@ func (t *Transport) Start(addr string) chan error {
 }
 
 func (t *Transport) Stop(ctx context.Context) error {
+	t.health.SetDraining()
+
+	select {
+	case <-time.After(t.drainDelay):
+	case <-ctx.Done():
+	}
+
 	return t.srv.Shutdown(ctx)
 }
+
+func (t *Transport) observeAuth(flow string, err error) {
+	switch {
+	case err == nil:
+		t.metrics.ObserveAuth(flow, "success")
+	case cerrors.KindOf(err) == cerrors.KindUnauthenticated, cerrors.KindOf(err) == cerrors.KindNotFound:
+		t.metrics.ObserveAuth(flow, "rejected")
+	default:
+		t.metrics.ObserveAuth(flow, "error")
+	}
+}
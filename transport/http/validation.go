@@ -1,13 +1,31 @@
 package http
 
+import "x-bank-users/vcodes"
+
 type (
-	validationErrors []string
+	FieldError struct {
+		Field   string      `json:"field"`
+		Code    vcodes.Code `json:"code"`
+		Message string      `json:"message"`
+
+		count int
+	}
+
+	validationErrors []FieldError
 
 	validatable interface {
 		validate() validationErrors
 	}
 )
 
-func (v *validationErrors) Add(e string) {
-	*v = append(*v, e)
+func (v *validationErrors) Add(field string, code vcodes.Code) {
+	v.AddCount(field, code, 1)
+}
+
+func (v *validationErrors) AddCount(field string, code vcodes.Code, n int) {
+	*v = append(*v, FieldError{
+		Field: field,
+		Code:  code,
+		count: n,
+	})
 }
@@ -0,0 +1,19 @@
+package vcodes
+
+type Code string
+
+const (
+	Required      Code = "REQUIRED"
+	InvalidValue  Code = "INVALID_VALUE"
+	InvalidFormat Code = "INVALID_FORMAT"
+	TooShort      Code = "TOO_SHORT"
+	TooLong       Code = "TOO_LONG"
+	InFuture      Code = "IN_FUTURE"
+	TooYoung      Code = "TOO_YOUNG"
+	InvalidRange  Code = "INVALID_RANGE"
+	Unknown       Code = "UNKNOWN"
+)
+
+var (
+	All = []Code{Required, InvalidValue, InvalidFormat, TooShort, TooLong, InFuture, TooYoung, InvalidRange, Unknown}
+)